@@ -37,8 +37,12 @@ func main() {
 
 	go func() {
 		var workout Workout
+		detector := pm5.NewStrokeDetector()
 		for r := range p.EventStream() {
 			fmt.Printf("%T %+v\n", r, r)
+			for _, s := range detector.Observe(time.Now(), r) {
+				fmt.Printf("stroke %d: %+v\n", s.DriveCounter, s)
+			}
 			switch resp := r.(type) {
 			case pm5.GetIDResponse:
 				fmt.Printf("PM ID: %s%s%s%s%s\n", string(resp.ASCIIDigit0), string(resp.ASCIIDigit1), string(resp.ASCIIDigit2), string(resp.ASCIIDigit3), string(resp.ASCIIDigit4))
@@ -53,8 +57,8 @@ func main() {
 					fmt.Println("workout state changed: ", resp.WorkoutStateString)
 				}
 			case pm5.GetStrokeStateResponse:
-				if workout.LastStrokeState.StrokeState > 2 && resp.StrokeState == 2 {
-					fmt.Println("new stroke!")
+				// Request the stats of the finished stroke when the next drive starts
+				if workout.LastStrokeState.StrokeState != pm5.StrokeStateDriving && resp.StrokeState == pm5.StrokeStateDriving {
 					_ = p.Send(ctx, pm5.GetStrokeStats(), pm5.GetPower())
 				}
 				workout.LastStrokeState = resp
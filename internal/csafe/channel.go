@@ -0,0 +1,65 @@
+package csafe
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+)
+
+// maxPending is the most bytes PollChannel buffers while waiting for the end of a frame. A frame can't exceed a
+// report, so more means the stop flag was lost.
+const maxPending = 512
+
+// Channel is a byte stream to the PM, such as the PM5 Bluetooth control characteristics, used by a Transport instead
+// of a HID device. Frames are written whole; responses may arrive split into chunks of any size.
+type Channel interface {
+	// Write writes a frame to the PM.
+	Write(ctx context.Context, frame []byte) error
+	// Notifications returns a channel of the bytes received from the PM, closed when ctx is done.
+	Notifications(ctx context.Context) <-chan []byte
+	Close() error
+}
+
+// PollChannel is Poll for a Transport on a Channel. It reassembles the frames split across the chunks received and
+// emits them once complete.
+func (t *Transport) PollChannel(ctx context.Context, chunks <-chan []byte) <-chan ExtendedResponseFrame {
+	out := make(chan ExtendedResponseFrame)
+
+	go func() {
+		defer close(out)
+
+		var pending []byte
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case chunk, ok := <-chunks:
+				if !ok {
+					t.log().Info("notification channel closed")
+					return
+				}
+
+				pending = append(pending, chunk...)
+				// Byte stuffing keeps the stop flag out of frame contents, so every stop flag ends a frame.
+				for {
+					end := bytes.IndexByte(pending, StopFrameFlag)
+					if end < 0 {
+						break
+					}
+
+					frame := pending[:end+1]
+					pending = pending[end+1:]
+					t.signalReceived()
+					t.receive(frame, out)
+				}
+
+				if len(pending) > maxPending {
+					t.log().Warn("discarding unterminated frame", slog.Int("length", len(pending)))
+					pending = nil
+				}
+			}
+		}
+	}()
+	return out
+}
@@ -1,6 +1,7 @@
 package csafe
 
 import (
+	"bytes"
 	"context"
 	"encoding/hex"
 	"errors"
@@ -37,21 +38,108 @@ const (
 	StateMachineStateBitMask = 0x0F
 )
 
+const (
+	// Always use report ID 0x02 and length 120 for sending. Report ID 0x01 is too short for long commands and causes
+	// checksum failures on response (which also comes on report ID 0x01). Report ID 0x04 doesn't always result in a
+	// response, so it is only used for frames that don't fit in report 0x02 when Transport.LargeReports is set.
+	defaultReportID     = 0x02
+	defaultReportLength = 120
+	largeReportID       = 0x04
+)
+
+var (
+	// ErrFrameTooLarge is returned when commands don't fit in a single frame, even on their own.
+	ErrFrameTooLarge = errors.New("frame too large for report")
+
+	// ErrMalformedFrame is returned when frame contents are truncated or otherwise cannot be decoded.
+	ErrMalformedFrame = errors.New("malformed frame")
+)
+
+// Framing selects the CSAFE frame format.
+type Framing int
+
+const (
+	FramingExtended Framing = iota // Extended frames (0xF0) carry destination and source addresses
+	FramingStandard                // Standard frames (0xF1) carry no addresses
+)
+
 type Transport struct {
 	Device        hid2.Device
+	Channel       Channel // Used instead of Device when set, e.g. for the PM5 Bluetooth control characteristics
 	ReportLengths map[byte]int
+	Framing       Framing       // Frame format used for sending (default FramingExtended)
+	LargeReports  bool          // Send frames too large for report 0x02 using report 0x04, if in ReportLengths
 	SendTimeout   time.Duration // Minimum time between sends if no message received (default 100ms)
 	SendBuffer    int           // Size of send buffer (default 100)
+	MaxRetries    int           // Times a frame reported bad or not ready is resent (default 3, negative disables)
+	Logger        *slog.Logger  // Logger for transport diagnostics (default slog.Default())
+
+	// OnError, if set, is called with a *FrameStatusError when a frame queued with Send is rejected by the PM or
+	// still fails after all retries. Errors for frames sent with Request are returned to the caller instead.
+	OnError func(error)
 
 	mu                    sync.Mutex
 	lastSendTime          time.Time
 	receivedSinceLastSend bool
+	inFlight              *outgoing // Frame awaiting its response
+	toggleSeen            bool
+	lastToggle            byte
+
+	sendOnce  sync.Once
+	received  chan struct{} // Wakes the sender when a message is received
+	cmdChan   chan Command
+	reqChan   chan *outgoing
+	retryChan chan *outgoing
+}
+
+// outgoing is a frame written to the device, tracked until the PM responds to it.
+type outgoing struct {
+	commands []Command
+	reply    chan result // Set for frames sent with Request
+	attempts int
+}
 
-	sendOnce sync.Once
-	cmdChan  chan Command
+type result struct {
+	frame ExtendedResponseFrame
+	err   error
+}
+
+// FrameStatusError reports that the PM did not accept a frame.
+type FrameStatusError struct {
+	Status   byte // One of FrameStatusReject, FrameStatusBad or FrameStatusNotReady
+	Attempts int  // Number of times the frame was sent
+}
+
+func (e *FrameStatusError) Error() string {
+	var status string
+	switch e.Status {
+	case FrameStatusReject:
+		status = "frame rejected"
+	case FrameStatusBad:
+		status = "bad frame"
+	case FrameStatusNotReady:
+		status = "not ready"
+	default:
+		status = fmt.Sprintf("frame status 0x%02X", e.Status)
+	}
+
+	if e.Attempts > 1 {
+		return fmt.Sprintf("%s after %d attempts", status, e.Attempts)
+	}
+	return status
+}
+
+func (t *Transport) log() *slog.Logger {
+	if t.Logger == nil {
+		return slog.Default()
+	}
+	return t.Logger
 }
 
 func (t *Transport) Close() error {
+	if t.Channel != nil {
+		return t.Channel.Close()
+	}
 	return t.Device.Close()
 }
 
@@ -67,86 +155,246 @@ func (t *Transport) Poll(ctx context.Context, reportChan <-chan hid2.Report) <-c
 
 			case report, ok := <-reportChan:
 				if !ok {
-					slog.Info("report channel closed")
+					t.log().Info("report channel closed")
 					return
 				}
 
-				// Signal that we've received a message
-				t.mu.Lock()
-				t.receivedSinceLastSend = true
-				t.mu.Unlock()
+				t.signalReceived()
 
 				if _, ok := t.ReportLengths[report.ID]; !ok {
-					slog.Warn("unknown report id", slog.Int("id", int(report.ID)))
-					continue
-				}
-
-				frames, err := parseFrames(report.Data)
-				if err != nil {
-					slog.Warn("CSAFE frame parsing failed", slog.Any("error", err))
+					t.log().Warn("unknown report id", slog.Int("id", int(report.ID)))
 					continue
 				}
 
-				for _, f := range frames {
-					out <- f
-				}
+				t.receive(report.Data, out)
 			}
 		}
 	}()
 	return out
 }
 
-func parseFrames(b []byte) ([]ExtendedResponseFrame, error) {
-	var frames []ExtendedResponseFrame
+// signalReceived records that a message was received, waking the sender.
+func (t *Transport) signalReceived() {
+	t.mu.Lock()
+	t.receivedSinceLastSend = true
+	t.mu.Unlock()
+
+	select {
+	case t.received <- struct{}{}:
+	default:
+	}
+}
+
+// receive parses the frames in b, delivers them to the frames in flight and emits them on out.
+func (t *Transport) receive(b []byte, out chan<- ExtendedResponseFrame) {
+	for _, f := range ParseFrames(t.log(), b) {
+		if t.duplicate(f) {
+			t.log().Debug("dropping duplicate frame", slog.Int("toggle", int(f.ResponseStatus.FrameToggle)))
+			continue
+		}
+
+		t.deliver(f)
+		out <- f
+	}
+}
+
+// rawFrame is a frame with the start and stop flags, byte stuffing and checksum removed.
+type rawFrame struct {
+	Framing            Framing
+	DestinationAddress byte // Extended frames only
+	SourceAddress      byte // Extended frames only
+	Contents           []byte
+}
+
+// unframe extracts every standard and extended frame with a valid checksum from b. Bytes outside of frames, such as
+// report padding, are ignored.
+func unframe(log *slog.Logger, b []byte) []rawFrame {
+	var frames []rawFrame
 
-	frameStartIdx, frameEndIdx := -1, -1
-	slog.Debug("parsing frames", slog.String("bytes", EncodeReportToString(b)))
+	frameStartIdx := -1
+	log.Debug("parsing frames", slog.String("bytes", EncodeReportToString(b)))
 	for i := 0; i < len(b); i++ {
-		if b[i] == ExtendedFrameStartFlag {
+		if b[i] == ExtendedFrameStartFlag || b[i] == StandardFrameStartFlag {
 			frameStartIdx = i
 			continue
 		}
 
-		if b[i] == StopFrameFlag && frameStartIdx != -1 {
-			frameEndIdx = i
-			slog.Debug("frame found", slog.String("stuffed bytes", EncodeReportToString(b[frameStartIdx+1:frameEndIdx])))
-			unstuffed, err := byteUnstuff(b[frameStartIdx+1 : frameEndIdx])
-			if err != nil {
-				frameStartIdx = -1
-				frameEndIdx = -1
-				slog.Warn("byte unstuffing failed", slog.Any("error", err))
-				continue
-			}
+		if b[i] != StopFrameFlag || frameStartIdx == -1 {
+			continue
+		}
 
-			slog.Debug("frame found", slog.String("unstuffed bytes", EncodeReportToString(unstuffed)))
-			computedChecksum := Checksum(unstuffed[2 : len(unstuffed)-1])
-			declaredChecksum := unstuffed[len(unstuffed)-1]
-			if declaredChecksum != computedChecksum {
-				frameStartIdx = -1
-				frameEndIdx = -1
+		startFlag := b[frameStartIdx]
+		stuffed := b[frameStartIdx+1 : i]
+		frameStartIdx = -1
+
+		log.Debug("frame found", slog.String("stuffed bytes", EncodeReportToString(stuffed)))
+		unstuffed, err := byteUnstuff(stuffed)
+		if err != nil {
+			log.Warn("byte unstuffing failed", slog.Any("error", err))
+			continue
+		}
 
-				slog.Warn("checksum validation failed", slog.Any("payload", declaredChecksum), slog.Any("computed", computedChecksum))
+		log.Debug("frame found", slog.String("unstuffed bytes", EncodeReportToString(unstuffed)))
+		f := rawFrame{Framing: FramingStandard}
+		body := unstuffed
+		if startFlag == ExtendedFrameStartFlag {
+			if len(body) < 3 {
+				log.Warn("extended frame too short", slog.Int("length", len(body)))
 				continue
 			}
+			f.Framing = FramingExtended
+			f.DestinationAddress = body[0]
+			f.SourceAddress = body[1]
+			body = body[2:]
+		}
+
+		if len(body) < 1 {
+			log.Warn("standard frame too short", slog.Int("length", len(body)))
+			continue
+		}
+
+		f.Contents = body[:len(body)-1]
+		computedChecksum := Checksum(f.Contents)
+		declaredChecksum := body[len(body)-1]
+		if declaredChecksum != computedChecksum {
+			log.Warn("checksum validation failed", slog.Any("payload", declaredChecksum), slog.Any("computed", computedChecksum))
+			continue
+		}
 
-			frames = append(frames, ExtendedResponseFrame{
-				ResponseStatus: ResponseStatus{
-					FrameToggle:         unstuffed[2] & FrameToggleBitMask,
-					PreviousFrameStatus: unstuffed[2] & PreviousFrameStatusBitMask,
-					StateMachineState:   unstuffed[2] & StateMachineStateBitMask,
-				},
-				DestinationAddress: unstuffed[0],
-				SourceAddress:      unstuffed[1],
-				Status:             unstuffed[2],
-				CommandResponses:   ParseResponses(unstuffed[3 : len(unstuffed)-1]),
-			})
+		frames = append(frames, f)
+	}
+
+	return frames
+}
+
+// ParseFrames extracts every well-formed response frame from b, logging and skipping malformed ones. Response frames
+// start with the status byte followed by the command responses.
+func ParseFrames(log *slog.Logger, b []byte) []ExtendedResponseFrame {
+	var frames []ExtendedResponseFrame
+
+	for _, f := range unframe(log, b) {
+		if len(f.Contents) < 1 {
+			log.Warn("response frame missing status byte")
+			continue
+		}
+
+		responses, err := ParseResponses(f.Contents[1:])
+		if err != nil {
+			log.Warn("response parsing failed", slog.Any("error", err))
+			continue
+		}
+
+		status := f.Contents[0]
+		frames = append(frames, ExtendedResponseFrame{
+			ResponseStatus: ResponseStatus{
+				FrameToggle:         status & FrameToggleBitMask,
+				PreviousFrameStatus: status & PreviousFrameStatusBitMask,
+				StateMachineState:   status & StateMachineStateBitMask,
+			},
+			Framing:            f.Framing,
+			DestinationAddress: f.DestinationAddress,
+			SourceAddress:      f.SourceAddress,
+			Status:             status,
+			CommandResponses:   responses,
+		})
+	}
+
+	return frames
+}
+
+// CommandFrame is a frame sent by the host, holding the commands for the PM.
+type CommandFrame struct {
+	Framing            Framing
+	DestinationAddress byte // Extended frames only
+	SourceAddress      byte // Extended frames only
+	Commands           []Request
+}
+
+// Request is a single command of a command frame. Short commands carry no data.
+type Request struct {
+	Command byte
+	Data    []byte
+}
+
+// ParseCommandFrames extracts every well-formed command frame from b, logging and skipping malformed ones. It is the
+// counterpart of ParseFrames for code standing in for a PM, such as an emulator.
+func ParseCommandFrames(log *slog.Logger, b []byte) []CommandFrame {
+	var frames []CommandFrame
+
+	for _, f := range unframe(log, b) {
+		commands, err := ParseCommands(f.Contents)
+		if err != nil {
+			log.Warn("command parsing failed", slog.Any("error", err))
+			continue
+		}
+
+		frames = append(frames, CommandFrame{
+			Framing:            f.Framing,
+			DestinationAddress: f.DestinationAddress,
+			SourceAddress:      f.SourceAddress,
+			Commands:           commands,
+		})
+	}
+
+	return frames
+}
+
+// ParseCommands splits frame contents, or the data of a wrapper command, into the individual commands. Commands from
+// 0x80 up are short commands made up of the command alone; lower ones are long commands followed by the data byte
+// count and the data. An error is returned if the contents are truncated.
+func ParseCommands(contents []byte) ([]Request, error) {
+	var commands []Request
+
+	for i := 0; i < len(contents); {
+		id := contents[i]
+		if id >= 0x80 {
+			commands = append(commands, Request{Command: id})
+			i++
+			continue
+		}
+
+		if i+1 >= len(contents) {
+			return nil, fmt.Errorf("%w: command 0x%02X missing data byte count", ErrMalformedFrame, id)
+		}
 
-			frameStartIdx = -1
-			frameEndIdx = -1
+		dataStart := i + 2
+		dataEnd := dataStart + int(contents[i+1])
+		if dataEnd > len(contents) {
+			return nil, fmt.Errorf("%w: command 0x%02X declares %d data bytes, %d available",
+				ErrMalformedFrame, id, contents[i+1], len(contents)-dataStart)
 		}
+
+		commands = append(commands, Request{Command: id, Data: bytes.Clone(contents[dataStart:dataEnd])})
+		i = dataEnd
+	}
+
+	return commands, nil
+}
+
+// EncodeResponseFrame builds a response frame, as sent by the PM, from the status byte and the command responses.
+// Extended frames are addressed to the primary PC host.
+func EncodeResponseFrame(framing Framing, status byte, responses []Response) []byte {
+	contents := []byte{status}
+	for _, r := range responses {
+		contents = append(contents, r.Command, byte(len(r.Data)))
+		contents = append(contents, r.Data...)
+	}
+
+	var body []byte
+	if framing == FramingExtended {
+		body = append(body, ExtendedFrameAddressPCHostPrimary, ExtendedFrameAddressDefaultSecondary)
 	}
+	body = append(body, contents...)
+	body = append(body, Checksum(contents))
 
-	return frames, nil
+	startFlag := byte(StandardFrameStartFlag)
+	if framing == FramingExtended {
+		startFlag = ExtendedFrameStartFlag
+	}
+
+	frame := []byte{startFlag}
+	frame = append(frame, byteStuff(body)...)
+	return append(frame, StopFrameFlag)
 }
 
 func EncodeReportToString(b []byte) string {
@@ -164,15 +412,96 @@ func EncodeReportToString(b []byte) string {
 
 type Command []byte
 
+// duplicate reports whether the frame repeats the previous one. The PM flips the frame toggle bit on every new
+// response frame, so an unchanged toggle means the previous response was sent again. Only frames received since the
+// last frame was written are compared: a lost response leaves the toggle unchanged between the responses either side
+// of it, and the next response must not be taken for a duplicate.
+func (t *Transport) duplicate(f ExtendedResponseFrame) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	toggle := f.ResponseStatus.FrameToggle
+	if t.toggleSeen && toggle == t.lastToggle {
+		return true
+	}
+
+	t.toggleSeen = true
+	t.lastToggle = toggle
+	return false
+}
+
+// deliver matches a received frame to the frame in flight. The PM answers frames in the order they are received, so
+// the first frame after a frame was written is the response to it; waitToSend makes sure a late response to the
+// previous frame arrives before a request is written. Frames reported bad or not ready are queued for
+// retransmission until MaxRetries is reached; rejected frames and exhausted retries are surfaced as a
+// *FrameStatusError.
+func (t *Transport) deliver(f ExtendedResponseFrame) {
+	t.mu.Lock()
+	sent := t.inFlight
+	t.inFlight = nil
+	t.mu.Unlock()
+
+	if sent == nil {
+		return
+	}
+
+	// Wake the sender, which may be waiting for this response before writing a request.
+	select {
+	case t.received <- struct{}{}:
+	default:
+	}
+
+	status := f.ResponseStatus.PreviousFrameStatus
+	if status == FrameStatusOK {
+		sent.complete(result{frame: f})
+		return
+	}
+
+	maxRetries := t.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = 3
+	}
+
+	if status != FrameStatusReject && sent.attempts <= maxRetries {
+		t.log().Debug("retransmitting frame", slog.Int("status", int(status)), slog.Int("attempts", sent.attempts))
+		select {
+		case t.retryChan <- sent:
+			return
+		default:
+		}
+	}
+
+	err := &FrameStatusError{Status: status, Attempts: sent.attempts}
+	t.log().Warn("frame failed", slog.Any("error", err))
+	if sent.reply == nil && t.OnError != nil {
+		t.OnError(err)
+	}
+	sent.complete(result{frame: f, err: err})
+}
+
+func (o *outgoing) complete(r result) {
+	if o.reply == nil {
+		return
+	}
+
+	select {
+	case o.reply <- r:
+	default:
+	}
+}
+
 // StartSender starts the background goroutine that processes buffered commands.
-// It must be called before Send. The context controls the lifetime of the sender.
+// It must be called before Send or Request. The context controls the lifetime of the sender.
 func (t *Transport) StartSender(ctx context.Context) {
 	t.sendOnce.Do(func() {
 		bufSize := t.SendBuffer
 		if bufSize <= 0 {
 			bufSize = 100
 		}
+		t.received = make(chan struct{}, 1)
 		t.cmdChan = make(chan Command, bufSize)
+		t.reqChan = make(chan *outgoing)
+		t.retryChan = make(chan *outgoing, 1)
 
 		go t.sendLoop(ctx)
 	})
@@ -184,10 +513,49 @@ func (t *Transport) sendLoop(ctx context.Context) {
 		timeout = 100 * time.Millisecond
 	}
 
+	// Batches of buffered commands waiting to be sent, one frame each
+	var queued [][]Command
+
 	for {
+		// Retransmissions take priority over new frames
+		select {
+		case o := <-t.retryChan:
+			if !t.waitToSend(ctx, timeout, o) {
+				return
+			}
+
+			t.writeFrame(ctx, o)
+			continue
+		default:
+		}
+
+		if len(queued) > 0 {
+			o := &outgoing{commands: queued[0]}
+			if !t.waitToSend(ctx, timeout, o) {
+				return
+			}
+
+			t.writeFrame(ctx, o)
+			queued = queued[1:]
+			continue
+		}
+
 		select {
 		case <-ctx.Done():
 			return
+		case o := <-t.retryChan:
+			if !t.waitToSend(ctx, timeout, o) {
+				return
+			}
+
+			t.writeFrame(ctx, o)
+		case req := <-t.reqChan:
+			// Requests are always sent in a frame of their own so the response can be matched to them.
+			if !t.waitToSend(ctx, timeout, req) {
+				return
+			}
+
+			t.writeFrame(ctx, req)
 		case cmd, ok := <-t.cmdChan:
 			if !ok {
 				return
@@ -208,36 +576,132 @@ func (t *Transport) sendLoop(ctx context.Context) {
 				}
 			}
 
-			// Wait until we can send
-			for {
-				t.mu.Lock()
-				canSend := t.receivedSinceLastSend || t.lastSendTime.IsZero() || time.Since(t.lastSendTime) >= timeout
-				if canSend {
-					t.lastSendTime = time.Now()
-					t.receivedSinceLastSend = false
-					t.mu.Unlock()
-					break
-				}
-				waitTime := timeout - time.Since(t.lastSendTime)
-				t.mu.Unlock()
+			queued = t.batch(commands)
+		}
+	}
+}
 
-				select {
-				case <-ctx.Done():
-					return
-				case <-time.After(waitTime):
-					// Continue loop to re-check conditions
-				}
-			}
+// batch splits commands into groups that each fit in a single frame once stuffed, preserving their order. Commands
+// are never split across frames.
+func (t *Transport) batch(commands []Command) [][]Command {
+	maxSize := t.maxFrameSize()
 
-			// Always use report ID 0x02 and length 120 for sending. Report ID 0x01 is too short for long commands and
-			// causes checksum failures on response (which also comes on report ID 0x01). Report ID 0x04 doesn't always
-			// result in a response.
-			report := hidReport(0x02, 120, extendedFrame(commands))
-			if err := t.Device.WriteReport(ctx, report); err != nil {
-				slog.Warn("failed to write report", slog.Any("error", err))
-			}
+	var batches [][]Command
+	var current []Command
+	for _, c := range commands {
+		if len(current) > 0 && len(t.frame(append(current, c))) > maxSize {
+			batches = append(batches, current)
+			current = nil
+		}
+		current = append(current, c)
+	}
+
+	if len(current) > 0 {
+		batches = append(batches, current)
+	}
+	return batches
+}
+
+// maxFrameSize returns the largest stuffed frame, including flags, that fits in a report.
+func (t *Transport) maxFrameSize() int {
+	if t.LargeReports && t.Channel == nil {
+		if length, ok := t.ReportLengths[largeReportID]; ok {
+			return length - 1
 		}
 	}
+	return defaultReportLength
+}
+
+// report wraps a frame in the smallest report it fits in.
+func (t *Transport) report(frame []byte) hid2.Report {
+	if len(frame) > defaultReportLength && t.LargeReports {
+		if length, ok := t.ReportLengths[largeReportID]; ok {
+			return hidReport(largeReportID, length-1, frame)
+		}
+	}
+	return hidReport(defaultReportID, defaultReportLength, frame)
+}
+
+// waitToSend blocks until o can be written: a response to the previous frame has been received or the timeout has
+// elapsed since it was sent. It returns false if the context is done first.
+//
+// Responses are matched to frames by order alone, so a response arriving after the next frame was written would be
+// taken for the response to that frame. When either frame belongs to a Request, waitToSend therefore waits up to a
+// second timeout for the response to the previous frame, so a late response is delivered to the frame it answers.
+func (t *Transport) waitToSend(ctx context.Context, timeout time.Duration, o *outgoing) bool {
+	for {
+		t.mu.Lock()
+		limit := timeout
+		canSend := t.receivedSinceLastSend
+		if sent := t.inFlight; sent != nil && (sent.reply != nil || o.reply != nil) {
+			limit = 2 * timeout
+			canSend = false
+		}
+		elapsed := time.Since(t.lastSendTime)
+		if canSend || t.lastSendTime.IsZero() || elapsed >= limit {
+			t.lastSendTime = time.Now()
+			t.receivedSinceLastSend = false
+			t.mu.Unlock()
+			return true
+		}
+		waitTime := limit - elapsed
+		t.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-t.received:
+			// Continue loop to re-check conditions
+		case <-time.After(waitTime):
+			// Continue loop to re-check conditions
+		}
+	}
+}
+
+func (t *Transport) writeFrame(ctx context.Context, o *outgoing) {
+	o.attempts++
+
+	t.mu.Lock()
+	t.inFlight = o
+	t.toggleSeen = false
+	t.mu.Unlock()
+
+	if t.Channel != nil {
+		if err := t.Channel.Write(ctx, t.frame(o.commands)); err != nil {
+			t.log().Warn("failed to write frame", slog.Any("error", err))
+		}
+		return
+	}
+
+	report := t.report(t.frame(o.commands))
+	if err := t.Device.WriteReport(ctx, report); err != nil {
+		t.log().Warn("failed to write report", slog.Any("error", err))
+	}
+}
+
+// frame creates a frame containing multiple commands using the configured framing
+func (t *Transport) frame(commands []Command) []byte {
+	if t.Framing == FramingStandard {
+		return standardFrame(commands)
+	}
+	return extendedFrame(commands)
+}
+
+// standardFrame creates a frame containing multiple commands
+func standardFrame(commands []Command) []byte {
+	var cmdBytes []byte
+	for _, cmd := range commands {
+		cmdBytes = append(cmdBytes, cmd...)
+	}
+
+	var frameContents []byte
+	frameContents = append(frameContents, cmdBytes...)
+	frameContents = append(frameContents, Checksum(cmdBytes))
+
+	frame := []byte{StandardFrameStartFlag}
+	frame = append(frame, byteStuff(frameContents)...)
+	frame = append(frame, StopFrameFlag)
+	return frame
 }
 
 // extendedFrame creates a frame containing multiple commands
@@ -269,15 +733,21 @@ func hidReport(id byte, length int, data []byte) hid2.Report {
 	}
 }
 
-// Send buffers commands for sending. It is non-blocking.
-// StartSender must be called before Send.
+// Send buffers commands for sending. It is non-blocking. Buffered commands are packed into as few frames as fit in a
+// report. StartSender must be called before Send.
 func (t *Transport) Send(_ context.Context, commands ...Command) error {
+	for _, c := range commands {
+		if len(t.frame([]Command{c})) > t.maxFrameSize() {
+			return ErrFrameTooLarge
+		}
+	}
+
 	for _, c := range commands {
 		select {
 		case t.cmdChan <- c:
-			slog.Debug("sending command", slog.String("command", hex.EncodeToString(c)))
+			t.log().Debug("sending command", slog.String("command", hex.EncodeToString(c)))
 		default:
-			slog.Warn("send buffer full, dropping command")
+			t.log().Warn("send buffer full, dropping command")
 			return errors.New("send buffer full")
 		}
 	}
@@ -285,6 +755,33 @@ func (t *Transport) Send(_ context.Context, commands ...Command) error {
 	return nil
 }
 
+// Request sends the commands in a frame of their own and waits for the PM's response to that frame. The response is
+// also emitted by Poll as usual. If the PM does not accept the frame, a *FrameStatusError is returned.
+// StartSender must be called before Request.
+func (t *Transport) Request(ctx context.Context, commands ...Command) (ExtendedResponseFrame, error) {
+	if len(t.frame(commands)) > t.maxFrameSize() {
+		return ExtendedResponseFrame{}, ErrFrameTooLarge
+	}
+
+	req := &outgoing{
+		commands: commands,
+		reply:    make(chan result, 1),
+	}
+
+	select {
+	case t.reqChan <- req:
+	case <-ctx.Done():
+		return ExtendedResponseFrame{}, ctx.Err()
+	}
+
+	select {
+	case r := <-req.reply:
+		return r.frame, r.err
+	case <-ctx.Done():
+		return ExtendedResponseFrame{}, ctx.Err()
+	}
+}
+
 type ExtendedFrame struct {
 	ExtendedStartFlag  []byte
 	DestinationAddress byte
@@ -319,42 +816,46 @@ type ResponseStatus struct {
 	StateMachineState   byte
 }
 
+// ExtendedResponseFrame is a response frame from the PM. Despite the name it also holds standard frames, which carry
+// no addresses; DestinationAddress and SourceAddress are zero for those.
 type ExtendedResponseFrame struct {
 	ResponseStatus     ResponseStatus
+	Framing            Framing
 	Status             byte
 	DestinationAddress byte
 	SourceAddress      byte
 	CommandResponses   []Response
 }
 
-func ParseResponses(frameContents []byte) []Response {
-	if len(frameContents) == 0 {
-		return nil
-	}
-
-	var cmdIdx int
+// ParseResponses splits frame contents into the individual command responses, each made up of the command, the data
+// byte count and the data. An error is returned if the contents are truncated.
+func ParseResponses(frameContents []byte) ([]Response, error) {
 	var responses []Response
 
-	for {
+	for cmdIdx := 0; cmdIdx < len(frameContents); {
+		if cmdIdx+1 >= len(frameContents) {
+			return nil, fmt.Errorf("%w: response 0x%02X missing data byte count", ErrMalformedFrame, frameContents[cmdIdx])
+		}
+
 		dataByteCount := frameContents[cmdIdx+1]
+		dataStart := cmdIdx + 1 + 1               // starts after the command and data size
+		dataEnd := dataStart + int(dataByteCount) // exclusive end index
+		if dataEnd > len(frameContents) {
+			return nil, fmt.Errorf("%w: response 0x%02X declares %d data bytes, %d available",
+				ErrMalformedFrame, frameContents[cmdIdx], dataByteCount, len(frameContents)-dataStart)
+		}
+
 		resp := Response{
 			Command:       frameContents[cmdIdx],
 			DataByteCount: dataByteCount,
 			Data:          make([]byte, dataByteCount),
 		}
-
-		dataStart := cmdIdx + 1 + 1                   // starts after the command and data size
-		dataEnd := dataStart + int(dataByteCount) - 1 // inclusive end index
-
-		copy(resp.Data, frameContents[dataStart:dataEnd+1])
+		copy(resp.Data, frameContents[dataStart:dataEnd])
 		responses = append(responses, resp)
-		cmdIdx = dataEnd + 1
-		if cmdIdx >= len(frameContents) {
-			break
-		}
+		cmdIdx = dataEnd
 	}
 
-	return responses
+	return responses, nil
 }
 
 type Response struct {
@@ -397,7 +898,7 @@ func byteUnstuff(input []byte) ([]byte, error) {
 
 		// Escape byte must be followed by a stuffing value
 		if i+1 >= len(input) {
-			return nil, fmt.Errorf("truncated escape sequence")
+			return nil, fmt.Errorf("%w: truncated escape sequence", ErrMalformedFrame)
 		}
 
 		i++
@@ -411,7 +912,7 @@ func byteUnstuff(input []byte) ([]byte, error) {
 		case 0x03:
 			out = append(out, 0xF3)
 		default:
-			return nil, fmt.Errorf("invalid escape value 0x%02X", input[i])
+			return nil, fmt.Errorf("%w: invalid escape value 0x%02X", ErrMalformedFrame, input[i])
 		}
 	}
 
@@ -0,0 +1,209 @@
+package csafe
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"reflect"
+	"testing"
+	"time"
+
+	hid2 "github.com/seagrayinc/gorow/internal/hid"
+)
+
+var framings = []struct {
+	name    string
+	framing Framing
+	encode  func([]Command) []byte
+}{
+	{name: "extended", framing: FramingExtended, encode: extendedFrame},
+	{name: "standard", framing: FramingStandard, encode: standardFrame},
+}
+
+func TestFrameRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		commands []Command
+	}{
+		{
+			name:     "single short command",
+			commands: []Command{ShortCommand(0x80)},
+		},
+		{
+			name: "multiple commands",
+			commands: []Command{
+				ShortCommand(0x91),
+				LongCommand(0x1A, []byte{0x6E, 0x01, 0x00}),
+			},
+		},
+		{
+			// Data bytes that collide with the frame flags must be stuffed
+			name:     "stuffed data",
+			commands: []Command{LongCommand(0x76, []byte{0xF0, 0xF1, 0xF2, 0xF3})},
+		},
+		{
+			// 0x80 ^ 0x70 = 0xF0, so the checksum itself must be stuffed
+			name:     "stuffed checksum",
+			commands: []Command{ShortCommand(0x80), ShortCommand(0x70)},
+		},
+	}
+
+	for _, f := range framings {
+		for _, tt := range tests {
+			t.Run(f.name+"/"+tt.name, func(t *testing.T) {
+				b := f.encode(tt.commands)
+
+				// Flags must only appear at the frame boundaries
+				for i, c := range b[1 : len(b)-1] {
+					if c >= ExtendedFrameStartFlag && c <= StopFrameFlag {
+						t.Fatalf("unstuffed flag 0x%02X at index %d in % x", c, i+1, b)
+					}
+				}
+
+				frames := unframe(slog.Default(), b)
+				if len(frames) != 1 {
+					t.Fatalf("frame count mismatch: got %d, want 1", len(frames))
+				}
+
+				if frames[0].Framing != f.framing {
+					t.Errorf("framing mismatch: got %d, want %d", frames[0].Framing, f.framing)
+				}
+
+				if want := bytes.Join(asBytes(tt.commands), nil); !bytes.Equal(frames[0].Contents, want) {
+					t.Errorf("contents mismatch:\ngot:  % x\nwant: % x", frames[0].Contents, want)
+				}
+			})
+		}
+	}
+}
+
+func TestParseFramesBothFramings(t *testing.T) {
+	for _, f := range framings {
+		t.Run(f.name, func(t *testing.T) {
+			// A response frame is a status byte followed by command responses. 0x81 ^ 0x92 ^ 0x05 ^ 0x30 ^ 0x30 ^
+			// 0x30 ^ 0x30 ^ 0xE5 = 0xF3, which exercises stuffing of the checksum.
+			b := f.encode([]Command{{0x81}, LongCommand(0x92, []byte{0x30, 0x30, 0x30, 0x30, 0xE5})})
+			if !bytes.HasSuffix(b, []byte{ByteStuffingFlag, 0x03, StopFrameFlag}) {
+				t.Fatalf("checksum not stuffed: % x", b)
+			}
+
+			// Surround the frame with report padding
+			b = append(append([]byte{0x00, 0x00}, b...), 0x00, 0x00)
+
+			frames := ParseFrames(slog.Default(), b)
+			if len(frames) != 1 {
+				t.Fatalf("frame count mismatch: got %d, want 1", len(frames))
+			}
+
+			got := frames[0]
+			wantStatus := ResponseStatus{FrameToggle: 0x80, PreviousFrameStatus: 0x00, StateMachineState: 0x01}
+			if got.ResponseStatus != wantStatus {
+				t.Errorf("status mismatch: got %+v, want %+v", got.ResponseStatus, wantStatus)
+			}
+
+			wantResponses := []Response{{Command: 0x92, DataByteCount: 5, Data: []byte{0x30, 0x30, 0x30, 0x30, 0xE5}}}
+			if !reflect.DeepEqual(got.CommandResponses, wantResponses) {
+				t.Errorf("responses mismatch:\ngot:  %+v\nwant: %+v", got.CommandResponses, wantResponses)
+			}
+		})
+	}
+}
+
+func TestCommandFrameRoundTrip(t *testing.T) {
+	commands := []Command{
+		ShortCommand(0x91),
+		LongCommand(0x7F, []byte{0x6E, 0x01, 0x00, 0xA0}),
+		LongCommand(0x76, []byte{0x13, 0x02, 0x01, 0xF1}),
+	}
+	want := []Request{
+		{Command: 0x91},
+		{Command: 0x7F, Data: []byte{0x6E, 0x01, 0x00, 0xA0}},
+		{Command: 0x76, Data: []byte{0x13, 0x02, 0x01, 0xF1}},
+	}
+
+	for _, f := range framings {
+		t.Run(f.name, func(t *testing.T) {
+			frames := ParseCommandFrames(slog.Default(), f.encode(commands))
+			if len(frames) != 1 {
+				t.Fatalf("frame count mismatch: got %d, want 1", len(frames))
+			}
+			if frames[0].Framing != f.framing {
+				t.Errorf("framing mismatch: got %d, want %d", frames[0].Framing, f.framing)
+			}
+			if !reflect.DeepEqual(frames[0].Commands, want) {
+				t.Errorf("commands mismatch:\ngot:  %+v\nwant: %+v", frames[0].Commands, want)
+			}
+		})
+	}
+}
+
+func TestParseCommandsTruncated(t *testing.T) {
+	for _, b := range [][]byte{{0x7F}, {0x7F, 0x03, 0xA0}} {
+		if _, err := ParseCommands(b); !errors.Is(err, ErrMalformedFrame) {
+			t.Errorf("% x: got %v, want ErrMalformedFrame", b, err)
+		}
+	}
+}
+
+func TestResponseFrameRoundTrip(t *testing.T) {
+	responses := []Response{
+		{Command: 0x92, DataByteCount: 5, Data: []byte{0x30, 0x30, 0x30, 0x30, 0x30}},
+		{Command: 0x7F, DataByteCount: 3, Data: []byte{0xBF, 0x01, 0xF2}},
+	}
+
+	for _, f := range framings {
+		t.Run(f.name, func(t *testing.T) {
+			frames := ParseFrames(slog.Default(), EncodeResponseFrame(f.framing, 0x85, responses))
+			if len(frames) != 1 {
+				t.Fatalf("frame count mismatch: got %d, want 1", len(frames))
+			}
+
+			got := frames[0]
+			wantStatus := ResponseStatus{FrameToggle: 0x80, PreviousFrameStatus: 0x00, StateMachineState: 0x05}
+			if got.ResponseStatus != wantStatus {
+				t.Errorf("status mismatch: got %+v, want %+v", got.ResponseStatus, wantStatus)
+			}
+			if !reflect.DeepEqual(got.CommandResponses, responses) {
+				t.Errorf("responses mismatch:\ngot:  %+v\nwant: %+v", got.CommandResponses, responses)
+			}
+		})
+	}
+}
+
+func TestTransportFraming(t *testing.T) {
+	for _, f := range framings {
+		t.Run(f.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			written := make(chan hid2.Report, 1)
+			mockHID := hid2.NewMockHID()
+			mockHID.OnWrite = func(r hid2.Report) { written <- r }
+
+			tr := &Transport{Device: mockHID, ReportLengths: map[byte]int{0x02: 121}, Framing: f.framing}
+			tr.StartSender(ctx)
+			if err := tr.Send(ctx, ShortCommand(0x80)); err != nil {
+				t.Fatalf("Send failed: %v", err)
+			}
+
+			select {
+			case r := <-written:
+				want := f.encode([]Command{ShortCommand(0x80)})
+				if !bytes.Equal(r.Data[:len(want)], want) {
+					t.Errorf("frame mismatch:\ngot:  % x\nwant: % x", r.Data[:len(want)], want)
+				}
+			case <-ctx.Done():
+				t.Fatal("timeout waiting for write")
+			}
+		})
+	}
+}
+
+func asBytes(commands []Command) [][]byte {
+	b := make([][]byte, len(commands))
+	for i, c := range commands {
+		b[i] = c
+	}
+	return b
+}
@@ -0,0 +1,76 @@
+package csafe
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+)
+
+func FuzzParseFrames(f *testing.F) {
+	f.Add(extendedFrame([]Command{{0x01}, LongCommand(0x92, []byte{0x30, 0x30, 0x30, 0x30, 0x30})}))
+	f.Add(standardFrame([]Command{{0x81}, LongCommand(0x1A, []byte{0x8D, 0x01, 0x01})}))
+	f.Add([]byte{ExtendedFrameStartFlag, 0x00, 0xFD, 0x01, 0x92, 0x05, ByteStuffingFlag, 0x00, 0xA6, StopFrameFlag})
+	f.Add([]byte{StandardFrameStartFlag, StopFrameFlag})
+	f.Add([]byte{ExtendedFrameStartFlag, 0x00, 0xFD, 0x01, 0x92, 0x05, 0x93, StopFrameFlag})
+
+	log := slog.New(slog.DiscardHandler)
+	f.Fuzz(func(t *testing.T, b []byte) {
+		for _, frame := range ParseFrames(log, b) {
+			for _, r := range frame.CommandResponses {
+				if int(r.DataByteCount) != len(r.Data) {
+					t.Fatalf("data byte count %d does not match %d data bytes", r.DataByteCount, len(r.Data))
+				}
+			}
+		}
+	})
+}
+
+func FuzzParseResponses(f *testing.F) {
+	f.Add([]byte{0x92, 0x05, 0x30, 0x30, 0x30, 0x30, 0x30})
+	f.Add([]byte{0x1A, 0x03, 0x8D, 0x01, 0x01})
+	f.Add([]byte{0x92})
+	f.Add([]byte{0x92, 0x05, 0x30})
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		responses, err := ParseResponses(b)
+		if err != nil {
+			return
+		}
+
+		// Well-formed contents must be fully accounted for by the responses
+		var n int
+		for _, r := range responses {
+			n += 2 + len(r.Data)
+		}
+		if n != len(b) {
+			t.Fatalf("responses cover %d of %d bytes", n, len(b))
+		}
+	})
+}
+
+func FuzzByteStuffing(f *testing.F) {
+	f.Add([]byte{0x00, 0xFD, 0x01, 0x92})
+	f.Add([]byte{0xF0, 0xF1, 0xF2, 0xF3})
+	f.Add([]byte{ByteStuffingFlag})
+	f.Add([]byte{ByteStuffingFlag, 0x04})
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		stuffed := byteStuff(b)
+		for _, c := range stuffed {
+			if c == ExtendedFrameStartFlag || c == StandardFrameStartFlag || c == StopFrameFlag {
+				t.Fatalf("unstuffed flag in % x", stuffed)
+			}
+		}
+
+		unstuffed, err := byteUnstuff(stuffed)
+		if err != nil {
+			t.Fatalf("byteUnstuff failed: %v", err)
+		}
+		if !bytes.Equal(unstuffed, b) {
+			t.Fatalf("round trip mismatch:\ngot:  % x\nwant: % x", unstuffed, b)
+		}
+
+		// Arbitrary input must not panic
+		_, _ = byteUnstuff(b)
+	})
+}
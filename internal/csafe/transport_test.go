@@ -0,0 +1,432 @@
+package csafe
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	hid2 "github.com/seagrayinc/gorow/internal/hid"
+)
+
+// responseReport builds an extended response frame from the PM to the host.
+func responseReport(status byte, contents ...byte) hid2.Report {
+	payload := append([]byte{status}, contents...)
+
+	var unstuffed []byte
+	unstuffed = append(unstuffed, ExtendedFrameAddressPCHostPrimary, ExtendedFrameAddressDefaultSecondary)
+	unstuffed = append(unstuffed, payload...)
+	unstuffed = append(unstuffed, Checksum(payload))
+
+	data := []byte{ExtendedFrameStartFlag}
+	data = append(data, byteStuff(unstuffed)...)
+	data = append(data, StopFrameFlag)
+	return hid2.Report{ID: 0x02, Data: data}
+}
+
+// fakePM answers every written frame like the PM does, flipping the frame toggle on each response. The n-th response
+// reports statuses[n] as the previous frame status, repeating the last status once they run out.
+type fakePM struct {
+	*hid2.MockHID
+
+	mu       sync.Mutex
+	statuses []byte
+	writes   int
+	reports  []hid2.Report
+}
+
+func newFakePM(statuses ...byte) *fakePM {
+	pm := &fakePM{MockHID: hid2.NewMockHID(), statuses: statuses}
+	pm.OnWrite = func(r hid2.Report) {
+		pm.mu.Lock()
+		pm.reports = append(pm.reports, r)
+		status := pm.statuses[min(pm.writes, len(pm.statuses)-1)]
+		toggle := byte(pm.writes%2) * FrameToggleBitMask
+		pm.writes++
+		pm.mu.Unlock()
+
+		go pm.Emit(responseReport(toggle | status | 0x01))
+	}
+	return pm
+}
+
+func (pm *fakePM) Reports() []hid2.Report {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	return append([]hid2.Report(nil), pm.reports...)
+}
+
+func (pm *fakePM) Writes() int {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	return pm.writes
+}
+
+// startTransport starts the sender and drains the polled frames for the lifetime of the context.
+func startTransport(ctx context.Context, tr *Transport) {
+	tr.StartSender(ctx)
+	frames := tr.Poll(ctx, tr.Device.PollReports(ctx))
+	go func() {
+		for range frames {
+		}
+	}()
+}
+
+func TestTransportDropsDuplicateFrames(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	mockHID := hid2.NewMockHID()
+	tr := &Transport{Device: mockHID, ReportLengths: map[byte]int{0x02: 121}}
+	frames := tr.Poll(ctx, mockHID.PollReports(ctx))
+
+	go func() {
+		mockHID.Emit(responseReport(0x01))
+		mockHID.Emit(responseReport(0x01)) // Same toggle: duplicate
+		mockHID.Emit(responseReport(FrameToggleBitMask | 0x01))
+	}()
+
+	var toggles []byte
+	for len(toggles) < 2 {
+		select {
+		case f := <-frames:
+			toggles = append(toggles, f.ResponseStatus.FrameToggle)
+		case <-ctx.Done():
+			t.Fatalf("timeout waiting for frames, got toggles %v", toggles)
+		}
+	}
+
+	if toggles[0] != 0x00 || toggles[1] != FrameToggleBitMask {
+		t.Errorf("unexpected frame toggles: %v", toggles)
+	}
+}
+
+func TestTransportRecoversFromLostResponse(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// The second response is lost, so the first and third carry the same frame toggle.
+	mockHID := hid2.NewMockHID()
+	var writes int
+	mockHID.OnWrite = func(hid2.Report) {
+		toggle := byte(writes%2) * FrameToggleBitMask
+		writes++
+		if writes != 2 {
+			go mockHID.Emit(responseReport(toggle | 0x01))
+		}
+	}
+	tr := &Transport{Device: mockHID, ReportLengths: map[byte]int{0x02: 121}, SendTimeout: 20 * time.Millisecond}
+	startTransport(ctx, tr)
+
+	if _, err := tr.Request(ctx, ShortCommand(0x80)); err != nil {
+		t.Fatalf("first Request failed: %v", err)
+	}
+
+	lostCtx, lostCancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer lostCancel()
+	if _, err := tr.Request(lostCtx, ShortCommand(0x80)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("second Request: got %v, want a timeout", err)
+	}
+
+	if _, err := tr.Request(ctx, ShortCommand(0x80)); err != nil {
+		t.Fatalf("third Request failed: %v", err)
+	}
+}
+
+func TestTransportMatchesLateResponses(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// The PM answers in order, echoing the commands of each frame, but the first response only arrives after the
+	// send timeout.
+	const timeout = 20 * time.Millisecond
+	mockHID := hid2.NewMockHID()
+	responses := make(chan hid2.Report, 10)
+	var writes int
+	mockHID.OnWrite = func(r hid2.Report) {
+		f := unframe(slog.Default(), r.Data)[0]
+		responses <- responseReport(byte(writes%2)*FrameToggleBitMask|0x01, f.Contents...)
+		writes++
+	}
+	go func() {
+		for i := 0; ; i++ {
+			select {
+			case r := <-responses:
+				if i == 0 {
+					time.Sleep(3 * timeout / 2)
+				}
+				mockHID.Emit(r)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	tr := &Transport{Device: mockHID, ReportLengths: map[byte]int{0x02: 121}, SendTimeout: timeout}
+	startTransport(ctx, tr)
+
+	if err := tr.Send(ctx, LongCommand(0x76, []byte{0x01})); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	time.Sleep(timeout / 2)
+
+	f, err := tr.Request(ctx, LongCommand(0x76, []byte{0x02}))
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	if len(f.CommandResponses) != 1 || !bytes.Equal(f.CommandResponses[0].Data, []byte{0x02}) {
+		t.Errorf("Request got the response to another frame: %+v", f.CommandResponses)
+	}
+}
+
+func TestTransportRetransmitsBadFrames(t *testing.T) {
+	tests := []struct {
+		name       string
+		statuses   []byte
+		maxRetries int
+		wantWrites int
+		wantErr    *FrameStatusError
+	}{
+		{
+			name:       "recovers after bad frame",
+			statuses:   []byte{FrameStatusBad, FrameStatusNotReady, FrameStatusOK},
+			wantWrites: 3,
+		},
+		{
+			name:       "gives up after max retries",
+			statuses:   []byte{FrameStatusBad},
+			maxRetries: 2,
+			wantWrites: 3,
+			wantErr:    &FrameStatusError{Status: FrameStatusBad, Attempts: 3},
+		},
+		{
+			name:       "rejected frames are not resent",
+			statuses:   []byte{FrameStatusReject},
+			wantWrites: 1,
+			wantErr:    &FrameStatusError{Status: FrameStatusReject, Attempts: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			pm := newFakePM(tt.statuses...)
+			tr := &Transport{Device: pm, ReportLengths: map[byte]int{0x02: 121}, MaxRetries: tt.maxRetries}
+			startTransport(ctx, tr)
+
+			_, err := tr.Request(ctx, ShortCommand(0x80))
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("Request failed: %v", err)
+			}
+			if tt.wantErr != nil {
+				var statusErr *FrameStatusError
+				if !errors.As(err, &statusErr) || *statusErr != *tt.wantErr {
+					t.Fatalf("error mismatch: got %v, want %v", err, tt.wantErr)
+				}
+			}
+
+			if got := pm.Writes(); got != tt.wantWrites {
+				t.Errorf("write count mismatch: got %d, want %d", got, tt.wantWrites)
+			}
+		})
+	}
+}
+
+func TestTransportReportsFailedSends(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	errs := make(chan error, 1)
+	tr := &Transport{
+		Device:        newFakePM(FrameStatusReject),
+		ReportLengths: map[byte]int{0x02: 121},
+		OnError:       func(err error) { errs <- err },
+	}
+	startTransport(ctx, tr)
+
+	if err := tr.Send(ctx, ShortCommand(0x80)); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	select {
+	case err := <-errs:
+		var statusErr *FrameStatusError
+		if !errors.As(err, &statusErr) || statusErr.Status != FrameStatusReject {
+			t.Errorf("unexpected error: %v", err)
+		}
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for error")
+	}
+}
+
+func TestTransportBatchesLargeBursts(t *testing.T) {
+	const commandCount = 500
+
+	tests := []struct {
+		name         string
+		largeReports bool
+		wantReportID byte
+		maxFrameSize int
+	}{
+		{name: "default reports", wantReportID: 0x02, maxFrameSize: 120},
+		{name: "large reports", largeReports: true, wantReportID: 0x04, maxFrameSize: 500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			pm := newFakePM(FrameStatusOK)
+			tr := &Transport{
+				Device:        pm,
+				ReportLengths: map[byte]int{0x01: 21, 0x02: 121, 0x04: 501},
+				LargeReports:  tt.largeReports,
+				SendBuffer:    commandCount,
+			}
+			startTransport(ctx, tr)
+
+			// Every command carries its index plus a byte that needs stuffing, so the stuffed size differs from the
+			// raw size.
+			for i := range commandCount {
+				if err := tr.Send(ctx, LongCommand(0x76, []byte{byte(i >> 8), byte(i), 0xF0})); err != nil {
+					t.Fatalf("Send failed: %v", err)
+				}
+			}
+
+			seen := make(map[int]int)
+			var reports []hid2.Report
+			for len(seen) < commandCount && ctx.Err() == nil {
+				time.Sleep(10 * time.Millisecond)
+
+				reports = pm.Reports()
+				clear(seen)
+				for _, r := range reports {
+					frames := unframe(slog.Default(), r.Data)
+					if len(frames) != 1 {
+						t.Fatalf("frame count mismatch: got %d, want 1 in % x", len(frames), r.Data)
+					}
+
+					responses, err := ParseResponses(frames[0].Contents)
+					if err != nil {
+						t.Fatalf("ParseResponses failed: %v", err)
+					}
+
+					for _, resp := range responses {
+						if resp.Command != 0x76 || resp.DataByteCount != 3 {
+							t.Fatalf("truncated command: %+v", resp)
+						}
+						seen[int(binary.BigEndian.Uint16(resp.Data))]++
+					}
+				}
+			}
+
+			for i := range commandCount {
+				if seen[i] != 1 {
+					t.Errorf("command %d sent %d times", i, seen[i])
+				}
+			}
+
+			var usedLargeReport bool
+			for _, r := range reports {
+				frameEnd := bytes.LastIndexByte(r.Data, StopFrameFlag) + 1
+				if frameEnd > tt.maxFrameSize {
+					t.Errorf("frame of %d bytes exceeds %d", frameEnd, tt.maxFrameSize)
+				}
+				usedLargeReport = usedLargeReport || r.ID == tt.wantReportID
+			}
+			if !usedLargeReport {
+				t.Errorf("no frame sent with report ID 0x%02X", tt.wantReportID)
+			}
+		})
+	}
+}
+
+func TestTransportRejectsOversizedCommands(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tr := &Transport{Device: hid2.NewMockHID(), ReportLengths: map[byte]int{0x02: 121}}
+	tr.StartSender(ctx)
+
+	oversized := LongCommand(0x76, make([]byte, 120))
+	if err := tr.Send(ctx, oversized); !errors.Is(err, ErrFrameTooLarge) {
+		t.Errorf("Send: unexpected error %v", err)
+	}
+	if _, err := tr.Request(ctx, oversized); !errors.Is(err, ErrFrameTooLarge) {
+		t.Errorf("Request: unexpected error %v", err)
+	}
+}
+
+// chunkedPM is a Channel answering every written frame with a response echoing its commands, split into chunks of
+// chunkSize bytes like the PM5 Bluetooth notifications.
+type chunkedPM struct {
+	chunkSize int
+	chunks    chan []byte
+	writes    chan []byte
+	toggle    byte
+}
+
+func (pm *chunkedPM) Write(_ context.Context, frame []byte) error {
+	pm.writes <- frame
+
+	f := unframe(slog.Default(), frame)[0]
+	response := responseReport(pm.toggle|0x01, f.Contents...).Data
+	pm.toggle ^= FrameToggleBitMask
+
+	// Noise between frames is ignored.
+	response = append([]byte{0x00}, response...)
+	for len(response) > 0 {
+		n := min(pm.chunkSize, len(response))
+		pm.chunks <- response[:n]
+		response = response[n:]
+	}
+	return nil
+}
+
+func (pm *chunkedPM) Notifications(context.Context) <-chan []byte {
+	return pm.chunks
+}
+
+func (pm *chunkedPM) Close() error {
+	return nil
+}
+
+func TestTransportChannel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	pm := &chunkedPM{chunkSize: 20, chunks: make(chan []byte, 100), writes: make(chan []byte, 10)}
+	tr := &Transport{Channel: pm}
+	tr.StartSender(ctx)
+	frames := tr.PollChannel(ctx, pm.Notifications(ctx))
+	go func() {
+		for range frames {
+		}
+	}()
+
+	// Long enough to span several chunks, with bytes that need stuffing.
+	data := bytes.Repeat([]byte{0x01, 0xF2, 0xF0}, 15)
+	for i := range 2 {
+		f, err := tr.Request(ctx, LongCommand(0x76, data))
+		if err != nil {
+			t.Fatalf("Request %d failed: %v", i, err)
+		}
+
+		want := []Response{{Command: 0x76, DataByteCount: byte(len(data)), Data: data}}
+		if len(f.CommandResponses) != 1 || !bytes.Equal(f.CommandResponses[0].Data, want[0].Data) ||
+			f.CommandResponses[0].Command != want[0].Command {
+			t.Errorf("responses mismatch:\ngot:  %+v\nwant: %+v", f.CommandResponses, want)
+		}
+
+		if frame := <-pm.writes; frame[0] != ExtendedFrameStartFlag || frame[len(frame)-1] != StopFrameFlag {
+			t.Errorf("written bytes are not a single frame: % x", frame)
+		}
+	}
+}
@@ -0,0 +1,320 @@
+package hid
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// A capture starts with captureMagic and a version byte, followed by one record per report:
+//
+//	direction   1 byte, DirectionOut or DirectionIn
+//	delay       uvarint, microseconds since the previous record (since the recording started for the first one)
+//	report ID   1 byte
+//	length      uvarint, length of the report data
+//	significant uvarint, length of the data without its trailing zeros
+//	data        the significant bytes
+//
+// HID reports have a fixed length and are mostly padding, so trimming the trailing zeros keeps captures small.
+const (
+	captureMagic   = "GOROWHID"
+	captureVersion = 1
+
+	// maxReportLength is the length of the largest PM5 report, which bounds the length of a captured report.
+	maxReportLength = 501
+)
+
+// ErrInvalidCapture is returned when reading a capture that was not written by a Recorder.
+var ErrInvalidCapture = errors.New("hid: invalid capture")
+
+// Direction tells whether a captured report was written to or read from the device.
+type Direction byte
+
+const (
+	DirectionOut Direction = iota // Written to the device
+	DirectionIn                   // Read from the device
+)
+
+// CaptureRecord is a single report of a capture.
+type CaptureRecord struct {
+	Time      time.Duration // Since the recording started
+	Direction Direction
+	Report    Report
+}
+
+// Recorder is a Device that passes all I/O through to another device and writes every report, with its time and
+// direction, to a capture. Failing to write the capture doesn't interrupt the device I/O; the first error is returned
+// by Close.
+type Recorder struct {
+	dev Device
+
+	mu    sync.Mutex
+	w     io.Writer
+	start time.Time
+	last  time.Duration
+	err   error
+}
+
+// NewRecorder returns a Recorder capturing the I/O of dev to w. The capture header is written right away.
+func NewRecorder(dev Device, w io.Writer) *Recorder {
+	r := &Recorder{dev: dev, w: w, start: time.Now()}
+	_, r.err = w.Write(append([]byte(captureMagic), captureVersion))
+	return r
+}
+
+func (r *Recorder) Close() error {
+	err := r.dev.Close()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return errors.Join(err, fmt.Errorf("hid: writing capture: %w", r.err))
+	}
+	return err
+}
+
+func (r *Recorder) WriteReport(ctx context.Context, report Report) error {
+	r.record(DirectionOut, report)
+	return r.dev.WriteReport(ctx, report)
+}
+
+func (r *Recorder) PollReports(ctx context.Context) <-chan Report {
+	in := r.dev.PollReports(ctx)
+	out := make(chan Report)
+
+	go func() {
+		defer close(out)
+
+		for report := range in {
+			r.record(DirectionIn, report)
+			select {
+			case out <- report:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// record appends a report to the capture. Each record is written with a single Write so a capture cut short by a
+// crash holds every record up to the last one.
+func (r *Recorder) record(d Direction, report Report) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err != nil {
+		return
+	}
+
+	// Times are kept in whole microseconds so the delays add up exactly. Records written concurrently may be
+	// timestamped out of order; keep the delays non-negative.
+	now := max(time.Since(r.start).Truncate(time.Microsecond), r.last)
+	delay := now - r.last
+	r.last = now
+
+	significant := len(report.Data)
+	for significant > 0 && report.Data[significant-1] == 0 {
+		significant--
+	}
+
+	b := []byte{byte(d)}
+	b = binary.AppendUvarint(b, uint64(delay/time.Microsecond))
+	b = append(b, report.ID)
+	b = binary.AppendUvarint(b, uint64(len(report.Data)))
+	b = binary.AppendUvarint(b, uint64(significant))
+	b = append(b, report.Data[:significant]...)
+
+	_, r.err = r.w.Write(b)
+}
+
+// ReadCapture reads all records of a capture written by a Recorder.
+func ReadCapture(r io.Reader) ([]CaptureRecord, error) {
+	br := bufio.NewReader(r)
+
+	header := make([]byte, len(captureMagic)+1)
+	if _, err := io.ReadFull(br, header); err != nil || string(header[:len(captureMagic)]) != captureMagic {
+		return nil, ErrInvalidCapture
+	}
+	if v := header[len(captureMagic)]; v != captureVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidCapture, v)
+	}
+
+	var records []CaptureRecord
+	var t time.Duration
+	for {
+		d, err := br.ReadByte()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		rec, err := readRecord(br, Direction(d))
+		if err != nil {
+			return nil, fmt.Errorf("%w: record %d: %w", ErrInvalidCapture, len(records), err)
+		}
+		t += rec.Time
+		rec.Time = t
+		records = append(records, rec)
+	}
+}
+
+// readRecord reads the rest of a record after its direction. The returned Time is the delay since the previous record.
+func readRecord(br *bufio.Reader, d Direction) (CaptureRecord, error) {
+	if d != DirectionOut && d != DirectionIn {
+		return CaptureRecord{}, fmt.Errorf("unknown direction %d", d)
+	}
+
+	delay, err := binary.ReadUvarint(br)
+	if err != nil {
+		return CaptureRecord{}, err
+	}
+	id, err := br.ReadByte()
+	if err != nil {
+		return CaptureRecord{}, err
+	}
+	length, err := binary.ReadUvarint(br)
+	if err != nil {
+		return CaptureRecord{}, err
+	}
+	significant, err := binary.ReadUvarint(br)
+	if err != nil {
+		return CaptureRecord{}, err
+	}
+	if length > maxReportLength {
+		return CaptureRecord{}, fmt.Errorf("%d byte report exceeds %d bytes", length, maxReportLength)
+	}
+	if significant > length {
+		return CaptureRecord{}, fmt.Errorf("%d significant bytes in a %d byte report", significant, length)
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(br, data[:significant]); err != nil {
+		return CaptureRecord{}, err
+	}
+
+	return CaptureRecord{
+		Time:      time.Duration(delay) * time.Microsecond,
+		Direction: d,
+		Report:    Report{ID: id, Data: data},
+	}, nil
+}
+
+// Replayer is a Device that plays back the reports read from the device in a capture. Written reports are discarded.
+// PollReports delivers the captured reports at their original times, multiplied by Scale, relative to when it was
+// called, and closes its channel after the last one.
+type Replayer struct {
+	records []CaptureRecord
+
+	// Scale multiplies the captured times: 1 (the default) replays in real time, 0.5 twice as fast and 0 without any
+	// delay.
+	Scale float64
+
+	// Lockstep holds back every report until as many reports have been written as had been before it in the capture,
+	// so responses are never delivered ahead of the requests they answer.
+	Lockstep bool
+
+	// OnWrite, if set, is called with every report written to the device.
+	OnWrite func(Report)
+
+	mu      sync.Mutex
+	writes  int
+	written chan struct{} // Signalled on every write
+}
+
+// NewReplayer reads a capture written by a Recorder and returns a Replayer for it.
+func NewReplayer(r io.Reader) (*Replayer, error) {
+	records, err := ReadCapture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Replayer{
+		records: records,
+		Scale:   1,
+		written: make(chan struct{}, 1),
+	}, nil
+}
+
+func (r *Replayer) Close() error {
+	return nil
+}
+
+func (r *Replayer) WriteReport(_ context.Context, report Report) error {
+	if r.OnWrite != nil {
+		r.OnWrite(report)
+	}
+
+	r.mu.Lock()
+	r.writes++
+	r.mu.Unlock()
+
+	select {
+	case r.written <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (r *Replayer) PollReports(ctx context.Context) <-chan Report {
+	out := make(chan Report)
+	start := time.Now()
+
+	go func() {
+		defer close(out)
+
+		writes := 0
+		for _, rec := range r.records {
+			if rec.Direction == DirectionOut {
+				writes++
+				continue
+			}
+
+			if r.Lockstep && !r.waitWrites(ctx, writes) {
+				return
+			}
+
+			if wait := time.Until(start.Add(time.Duration(float64(rec.Time) * r.Scale))); wait > 0 {
+				select {
+				case <-time.After(wait):
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case out <- rec.Report:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// waitWrites waits until at least n reports have been written. It returns false if ctx is done first.
+func (r *Replayer) waitWrites(ctx context.Context, n int) bool {
+	for {
+		r.mu.Lock()
+		writes := r.writes
+		r.mu.Unlock()
+		if writes >= n {
+			return true
+		}
+
+		select {
+		case <-r.written:
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package hid
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRecordReplay(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	request := Report{ID: 0x01, Data: append([]byte{0xF1, 0x91, 0x91, 0xF2}, make([]byte, 16)...)}
+	response := Report{ID: 0x02, Data: append([]byte{0xF0, 0x00, 0xFD, 0x01, 0x91, 0x00, 0x90, 0xF2}, make([]byte, 113)...)}
+
+	mock := NewMockHID()
+	mock.OnWrite = func(Report) {
+		go mock.Emit(response)
+	}
+
+	var capture bytes.Buffer
+	rec := NewRecorder(mock, &capture)
+	reports := rec.PollReports(ctx)
+	for range 2 {
+		if err := rec.WriteReport(ctx, request); err != nil {
+			t.Fatalf("WriteReport failed: %v", err)
+		}
+		if got := <-reports; !reflect.DeepEqual(got, response) {
+			t.Fatalf("report mismatch:\ngot:  %v\nwant: %v", got, response)
+		}
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	records, err := ReadCapture(bytes.NewReader(capture.Bytes()))
+	if err != nil {
+		t.Fatalf("ReadCapture failed: %v", err)
+	}
+	var directions []Direction
+	for i, r := range records {
+		directions = append(directions, r.Direction)
+		if i > 0 && r.Time < records[i-1].Time {
+			t.Errorf("record %d is earlier than the previous one", i)
+		}
+	}
+	if want := []Direction{DirectionOut, DirectionIn, DirectionOut, DirectionIn}; !reflect.DeepEqual(directions, want) {
+		t.Fatalf("directions mismatch: got %v, want %v", directions, want)
+	}
+	if !reflect.DeepEqual(records[0].Report, request) || !reflect.DeepEqual(records[1].Report, response) {
+		t.Errorf("reports not captured as written and read")
+	}
+
+	replayer, err := NewReplayer(bytes.NewReader(capture.Bytes()))
+	if err != nil {
+		t.Fatalf("NewReplayer failed: %v", err)
+	}
+	replayer.Scale, replayer.Lockstep = 0, true
+
+	replayed := replayer.PollReports(ctx)
+	select {
+	case <-replayed:
+		t.Fatal("response replayed before the request was written")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	for range 2 {
+		if err := replayer.WriteReport(ctx, request); err != nil {
+			t.Fatalf("WriteReport failed: %v", err)
+		}
+		if got := <-replayed; !reflect.DeepEqual(got, response) {
+			t.Fatalf("replayed report mismatch:\ngot:  %v\nwant: %v", got, response)
+		}
+	}
+	if _, ok := <-replayed; ok {
+		t.Error("replay not finished after the last report")
+	}
+}
+
+func TestReplayTiming(t *testing.T) {
+	var capture bytes.Buffer
+	capture.WriteString(captureMagic)
+	capture.WriteByte(captureVersion)
+	// Two reports read 100ms apart
+	capture.Write([]byte{byte(DirectionIn), 0x00, 0x02, 0x01, 0x01, 0xAA})
+	capture.Write([]byte{byte(DirectionIn), 0xA0, 0x8D, 0x06, 0x02, 0x01, 0x01, 0xBB})
+
+	replayer, err := NewReplayer(&capture)
+	if err != nil {
+		t.Fatalf("NewReplayer failed: %v", err)
+	}
+	replayer.Scale = 0.5
+
+	start := time.Now()
+	var got []Report
+	for r := range replayer.PollReports(context.Background()) {
+		got = append(got, r)
+	}
+	elapsed := time.Since(start)
+
+	want := []Report{{ID: 0x02, Data: []byte{0xAA}}, {ID: 0x02, Data: []byte{0xBB}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("reports mismatch:\ngot:  %v\nwant: %v", got, want)
+	}
+	if elapsed < 50*time.Millisecond || elapsed > 500*time.Millisecond {
+		t.Errorf("replay took %v, want about 50ms", elapsed)
+	}
+}
+
+func TestReadCaptureInvalid(t *testing.T) {
+	tests := map[string][]byte{
+		"not a capture":   []byte("hello"),
+		"unknown version": []byte(captureMagic + "\x09"),
+		"truncated":       []byte(captureMagic + "\x01\x01\x00\x02\x05\x05\xAA"),
+		"bad direction":   []byte(captureMagic + "\x01\x07\x00\x02\x00\x00"),
+		"oversized":       []byte(captureMagic + "\x01\x01\x00\x02\x80\x80\x80\x80\x10\x00"),
+	}
+
+	for name, b := range tests {
+		if _, err := ReadCapture(bytes.NewReader(b)); !errors.Is(err, ErrInvalidCapture) {
+			t.Errorf("%s: got %v, want ErrInvalidCapture", name, err)
+		}
+	}
+}
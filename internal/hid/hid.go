@@ -11,6 +11,20 @@ type Device interface {
 	PollReports(context.Context) <-chan Report
 }
 
+// Report represents an individual report. The Data field includes the complete buffer based on the device's
+// descriptors.
+type Report struct {
+	ID   byte
+	Data []byte
+}
+
+func (r Report) Bytes() []byte {
+	b := make([]byte, len(r.Data)+1)
+	b[0] = r.ID
+	copy(b[1:], r.Data)
+	return b
+}
+
 // Info represents a HID device descriptor.
 type Info struct {
 	Path         string
@@ -18,13 +32,30 @@ type Info struct {
 	ProductID    uint16
 	Product      string
 	Manufacturer string
+	SerialNumber string
 }
 
 // Manager enumerates and opens HID devices.
 type Manager interface {
+	// Enumerate lists the connected devices matching the vendor and product ID.
+	Enumerate(vendorID, productID uint16) ([]Info, error)
+	// Open opens the device at the given OS-specific path.
+	Open(path string) (Device, error)
+	// OpenVIDPID opens the first connected device matching the vendor and product ID.
 	OpenVIDPID(vendorID, productID uint16) (Device, error)
 }
 
+// filterVIDPID returns the devices matching the vendor and product ID.
+func filterVIDPID(devs []Info, vendorID, productID uint16) []Info {
+	var matched []Info
+	for _, d := range devs {
+		if d.VendorID == vendorID && d.ProductID == productID {
+			matched = append(matched, d)
+		}
+	}
+	return matched
+}
+
 // NewManager returns the OS-specific HID manager.
 func NewManager() (Manager, error) {
 	return newManager()
@@ -0,0 +1,215 @@
+//go:build linux
+
+package hid
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	sysfsHidrawRoot = "/sys/class/hidraw"
+	devRoot         = "/dev"
+
+	// hidrawBufferSize is large enough for any report the kernel will hand back from a single read. hidraw returns
+	// exactly one report per read, prefixed with the report ID for devices that use numbered reports.
+	hidrawBufferSize = 4096
+)
+
+type linuxManager struct {
+	sysfsRoot string // Directory holding one entry per hidraw node (normally /sys/class/hidraw)
+	devRoot   string // Directory holding the hidraw device nodes (normally /dev)
+}
+
+func newManager() (Manager, error) {
+	return &linuxManager{
+		sysfsRoot: sysfsHidrawRoot,
+		devRoot:   devRoot,
+	}, nil
+}
+
+func (m *linuxManager) list() ([]Info, error) {
+	entries, err := os.ReadDir(m.sysfsRoot)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s failed: %v", m.sysfsRoot, err)
+	}
+
+	var devices []Info
+	for _, e := range entries {
+		uevent, err := os.ReadFile(filepath.Join(m.sysfsRoot, e.Name(), "device", "uevent"))
+		if err != nil {
+			continue
+		}
+
+		info, ok := parseUevent(uevent)
+		if !ok {
+			continue
+		}
+
+		// The HID device's parent's parent is the USB device, which exposes the string descriptors. The path is built
+		// without filepath.Join so that ".." is resolved by the kernel after following the device symlink.
+		usbDevice := m.sysfsRoot + "/" + e.Name() + "/device/../.."
+		if b, err := os.ReadFile(usbDevice + "/manufacturer"); err == nil {
+			info.Manufacturer = strings.TrimSpace(string(b))
+		}
+
+		info.Path = filepath.Join(m.devRoot, e.Name())
+		devices = append(devices, info)
+	}
+
+	return devices, nil
+}
+
+// parseUevent extracts the vendor ID, product ID, name and serial number from the uevent file of a HID device, e.g.
+//
+//	HID_ID=0003:000017A4:0000001E
+//	HID_NAME=Concept2 Performance Monitor 5 (PM5)
+//	HID_UNIQ=430123456
+func parseUevent(b []byte) (Info, bool) {
+	var info Info
+	var found bool
+
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok {
+			continue
+		}
+
+		switch key {
+		case "HID_ID":
+			parts := strings.Split(value, ":")
+			if len(parts) != 3 {
+				return Info{}, false
+			}
+
+			vid, err := strconv.ParseUint(parts[1], 16, 32)
+			if err != nil {
+				return Info{}, false
+			}
+			pid, err := strconv.ParseUint(parts[2], 16, 32)
+			if err != nil {
+				return Info{}, false
+			}
+
+			info.VendorID = uint16(vid)
+			info.ProductID = uint16(pid)
+			found = true
+		case "HID_NAME":
+			info.Product = value
+		case "HID_UNIQ":
+			info.SerialNumber = value
+		}
+	}
+
+	return info, found
+}
+
+func (m *linuxManager) open(info Info) (Device, error) {
+	f, err := os.OpenFile(info.Path, os.O_RDWR, 0)
+	if err != nil {
+		return nil, fmt.Errorf("open failed: %v", err)
+	}
+
+	return &linuxDevice{
+		file: f,
+		path: info.Path,
+	}, nil
+}
+
+func (m *linuxManager) Enumerate(vendorID, productID uint16) ([]Info, error) {
+	devs, err := m.list()
+	if err != nil {
+		return nil, err
+	}
+	return filterVIDPID(devs, vendorID, productID), nil
+}
+
+func (m *linuxManager) Open(path string) (Device, error) {
+	return m.open(Info{Path: path})
+}
+
+func (m *linuxManager) OpenVIDPID(vendorID, productID uint16) (Device, error) {
+	devs, err := m.Enumerate(vendorID, productID)
+	if err != nil {
+		return nil, err
+	}
+	if len(devs) > 0 {
+		return m.open(devs[0])
+	}
+
+	return nil, fmt.Errorf("device not found (VID:0x%04X PID:0x%04X)", vendorID, productID)
+}
+
+type linuxDevice struct {
+	file *os.File
+	path string
+}
+
+func (d *linuxDevice) WriteReport(ctx context.Context, r Report) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// hidraw expects the report ID as the first byte followed by the report data, matching the Windows layout.
+	if _, err := d.file.Write(r.Bytes()); err != nil {
+		return fmt.Errorf("write failed: %v", err)
+	}
+
+	return nil
+}
+
+// PollReports starts a goroutine that constantly reads reports from the device and emits them to the returned channel.
+func (d *linuxDevice) PollReports(ctx context.Context) <-chan Report {
+	out := make(chan Report)
+
+	// A previous PollReports leaves the deadline that unblocked its read behind.
+	_ = d.file.SetReadDeadline(time.Time{})
+
+	// Unblock the pending read once the context is done. Device nodes, FIFOs and ptys all support deadlines.
+	stop := context.AfterFunc(ctx, func() {
+		_ = d.file.SetReadDeadline(time.Now())
+	})
+
+	go func() {
+		defer close(out)
+		defer stop()
+
+		buf := make([]byte, hidrawBufferSize)
+		for {
+			read, err := d.file.Read(buf)
+			if err != nil {
+				if ctx.Err() == nil {
+					slog.Info("read failed", slog.String("path", d.path), slog.Any("error", err))
+				}
+				return
+			}
+
+			if read > 0 {
+				report := Report{
+					ID:   buf[0],
+					Data: append([]byte(nil), buf[1:read]...),
+				}
+
+				select {
+				case out <- report:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out
+}
+
+func (d *linuxDevice) Close() error {
+	return d.file.Close()
+}
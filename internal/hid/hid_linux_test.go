@@ -0,0 +1,155 @@
+//go:build linux
+
+package hid
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// fakeHidraw creates a fake sysfs tree and a FIFO standing in for the /dev/hidrawN node of each uevent.
+func fakeHidraw(t *testing.T, uevents map[string]string) *linuxManager {
+	t.Helper()
+
+	m := &linuxManager{
+		sysfsRoot: filepath.Join(t.TempDir(), "sys"),
+		devRoot:   filepath.Join(t.TempDir(), "dev"),
+	}
+	if err := os.MkdirAll(m.devRoot, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, uevent := range uevents {
+		dir := filepath.Join(m.sysfsRoot, name, "device")
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "uevent"), []byte(uevent), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := syscall.Mkfifo(filepath.Join(m.devRoot, name), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return m
+}
+
+func TestLinuxManagerOpenVIDPID(t *testing.T) {
+	m := fakeHidraw(t, map[string]string{
+		"hidraw0": "DRIVER=hid-generic\nHID_ID=0003:0000046D:0000C52B\nHID_NAME=Logitech USB Receiver\n",
+		"hidraw1": "DRIVER=hid-generic\nHID_ID=0003:000017A4:0000001E\nHID_NAME=Concept2 Performance Monitor 5 (PM5)\nHID_UNIQ=430123456\n",
+	})
+
+	devs, err := m.list()
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if len(devs) != 2 {
+		t.Fatalf("device count mismatch: got %d, want 2", len(devs))
+	}
+
+	pm5s, err := m.Enumerate(0x17A4, 0x001E)
+	if err != nil {
+		t.Fatalf("Enumerate failed: %v", err)
+	}
+	if len(pm5s) != 1 || pm5s[0].SerialNumber != "430123456" || pm5s[0].Product != "Concept2 Performance Monitor 5 (PM5)" {
+		t.Errorf("unexpected devices: %+v", pm5s)
+	}
+
+	dev, err := m.OpenVIDPID(0x17A4, 0x001E)
+	if err != nil {
+		t.Fatalf("OpenVIDPID failed: %v", err)
+	}
+	defer dev.Close()
+
+	if got, want := dev.(*linuxDevice).path, filepath.Join(m.devRoot, "hidraw1"); got != want {
+		t.Errorf("path mismatch: got %s, want %s", got, want)
+	}
+
+	if _, err := m.OpenVIDPID(0x17A4, 0x0FFF); err == nil {
+		t.Error("expected error for missing device")
+	}
+}
+
+func TestLinuxDeviceReports(t *testing.T) {
+	m := fakeHidraw(t, map[string]string{
+		"hidraw0": "HID_ID=0003:000017A4:0000001E\n",
+	})
+
+	dev, err := m.OpenVIDPID(0x17A4, 0x001E)
+	if err != nil {
+		t.Fatalf("OpenVIDPID failed: %v", err)
+	}
+	defer dev.Close()
+
+	node, err := os.OpenFile(filepath.Join(m.devRoot, "hidraw0"), os.O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer node.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// Writes are sent as the report ID followed by the report data
+	if err := dev.WriteReport(ctx, Report{ID: 0x02, Data: []byte{0xF0, 0xFD, 0x00, 0x80, 0x80, 0xF2}}); err != nil {
+		t.Fatalf("WriteReport failed: %v", err)
+	}
+
+	buf := make([]byte, 64)
+	n, err := node.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []byte{0x02, 0xF0, 0xFD, 0x00, 0x80, 0x80, 0xF2}; !bytes.Equal(buf[:n], want) {
+		t.Errorf("written report mismatch:\ngot:  % x\nwant: % x", buf[:n], want)
+	}
+
+	// Reads are split into the report ID and the report data
+	pollCtx, stopPolling := context.WithCancel(ctx)
+	reports := dev.PollReports(pollCtx)
+	if _, err := node.Write([]byte{0x01, 0xF0, 0x00, 0xFD, 0x01, 0x01, 0xF2}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case r := <-reports:
+		if r.ID != 0x01 || !bytes.Equal(r.Data, []byte{0xF0, 0x00, 0xFD, 0x01, 0x01, 0xF2}) {
+			t.Errorf("report mismatch: got %+v", r)
+		}
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for report")
+	}
+
+	// Cancelling the context unblocks the pending read and closes the channel
+	stopPolling()
+	select {
+	case _, ok := <-reports:
+		if ok {
+			t.Error("expected report channel to be closed")
+		}
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for report channel to close")
+	}
+
+	// Polling again reads past the deadline that unblocked the previous read
+	reports = dev.PollReports(ctx)
+	if _, err := node.Write([]byte{0x01, 0xF0, 0x00, 0xFD, 0x01, 0x09, 0xF2}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case r, ok := <-reports:
+		if !ok || r.ID != 0x01 || !bytes.Equal(r.Data, []byte{0xF0, 0x00, 0xFD, 0x01, 0x09, 0xF2}) {
+			t.Errorf("report mismatch after polling again: got %+v", r)
+		}
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for report after polling again")
+	}
+}
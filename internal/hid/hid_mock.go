@@ -2,15 +2,22 @@ package hid
 
 import (
 	"context"
+	"sync"
 )
 
 type MockHID struct {
-	reports chan Report
+	reports   chan Report
+	done      chan struct{}
+	closeOnce sync.Once
+
+	// OnWrite, if set, is called with every report written to the device.
+	OnWrite func(Report)
 }
 
 func NewMockHID() *MockHID {
 	return &MockHID{
 		reports: make(chan Report),
+		done:    make(chan struct{}),
 	}
 }
 
@@ -18,20 +25,41 @@ func (m *MockHID) Close() error {
 	return nil
 }
 
-func (m *MockHID) WriteReport(_ context.Context, _ Report) error {
+func (m *MockHID) WriteReport(_ context.Context, r Report) error {
+	if m.OnWrite != nil {
+		m.OnWrite(r)
+	}
 	return nil
 }
 
 func (m *MockHID) PollReports(ctx context.Context) <-chan Report {
+	out := make(chan Report)
+
 	go func() {
-		<-ctx.Done()
-		close(m.reports)
+		defer close(out)
+		defer m.closeOnce.Do(func() { close(m.done) })
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case r := <-m.reports:
+				select {
+				case out <- r:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
 	}()
 
-	return m.reports
+	return out
 }
 
+// Emit blocks until the report is polled. Reports emitted after polling has stopped are discarded.
 func (m *MockHID) Emit(r Report) {
-	//b := make([]byte, 501)
-	m.reports <- Report{ID: r.ID, Data: r.Data}
+	select {
+	case m.reports <- Report{ID: r.ID, Data: r.Data}:
+	case <-m.done:
+	}
 }
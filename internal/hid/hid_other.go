@@ -0,0 +1,12 @@
+//go:build !windows && !linux
+
+package hid
+
+import (
+	"errors"
+	"runtime"
+)
+
+func newManager() (Manager, error) {
+	return nil, errors.New("hid: unsupported platform " + runtime.GOOS)
+}
@@ -179,7 +179,7 @@ func (m *winManager) list() ([]Info, error) {
 		attrs.Size = uint32(unsafe.Sizeof(attrs))
 		r, _, _ = procHidD_GetAttributes.Call(uintptr(h), uintptr(unsafe.Pointer(&attrs)))
 
-		var manufacturer, product string
+		var manufacturer, product, serialNumber string
 		if r != 0 {
 			mfr := make([]uint16, 256)
 			procHidD_GetManufacturerString.Call(uintptr(h), uintptr(unsafe.Pointer(&mfr[0])), uintptr(len(mfr)*2))
@@ -188,6 +188,10 @@ func (m *winManager) list() ([]Info, error) {
 			prod := make([]uint16, 256)
 			procHidD_GetProductString.Call(uintptr(h), uintptr(unsafe.Pointer(&prod[0])), uintptr(len(prod)*2))
 			product = windows.UTF16ToString(prod)
+
+			serial := make([]uint16, 256)
+			procHidD_GetSerialNumberString.Call(uintptr(h), uintptr(unsafe.Pointer(&serial[0])), uintptr(len(serial)*2))
+			serialNumber = windows.UTF16ToString(serial)
 		}
 
 		_ = windows.CloseHandle(h)
@@ -199,6 +203,7 @@ func (m *winManager) list() ([]Info, error) {
 				ProductID:    attrs.ProductID,
 				Manufacturer: manufacturer,
 				Product:      product,
+				SerialNumber: serialNumber,
 			})
 		}
 	}
@@ -301,15 +306,25 @@ func (m *winManager) open(info Info) (Device, error) {
 	}, nil
 }
 
-func (m *winManager) OpenVIDPID(vendorID, productID uint16) (Device, error) {
+func (m *winManager) Enumerate(vendorID, productID uint16) ([]Info, error) {
 	devs, err := m.list()
 	if err != nil {
 		return nil, err
 	}
-	for _, d := range devs {
-		if d.VendorID == vendorID && d.ProductID == productID {
-			return m.open(d)
-		}
+	return filterVIDPID(devs, vendorID, productID), nil
+}
+
+func (m *winManager) Open(path string) (Device, error) {
+	return m.open(Info{Path: path})
+}
+
+func (m *winManager) OpenVIDPID(vendorID, productID uint16) (Device, error) {
+	devs, err := m.Enumerate(vendorID, productID)
+	if err != nil {
+		return nil, err
+	}
+	if len(devs) > 0 {
+		return m.open(devs[0])
 	}
 
 	return nil, fmt.Errorf("device not found (VID:0x%04X PID:0x%04X)", vendorID, productID)
@@ -323,20 +338,6 @@ type winDevice struct {
 	featureLen int
 }
 
-// Report represents an individual report. The Data field includes the complete buffer based on the device's
-// descriptors.
-type Report struct {
-	ID   byte
-	Data []byte
-}
-
-func (r Report) Bytes() []byte {
-	b := make([]byte, len(r.Data)+1)
-	b[0] = r.ID
-	copy(b[1:], r.Data)
-	return b
-}
-
 // PollReports starts a goroutine that constantly reads reports from the device and emits them to the returned channel.
 func (d *winDevice) PollReports(ctx context.Context) <-chan Report {
 	out := make(chan Report)
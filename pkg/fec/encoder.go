@@ -0,0 +1,151 @@
+package fec
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/seagrayinc/gorow/pkg/pm5"
+)
+
+// Encoder follows the events of a PM into the Telemetry the FE pages are encoded from.
+type Encoder struct {
+	mu        sync.Mutex
+	telemetry Telemetry
+	product   Product
+}
+
+// NewEncoder returns an Encoder for the given product, with the equipment ready. The serial number of the product is
+// replaced by the PM's once a GetSerialResponse is observed.
+func NewEncoder(product Product) *Encoder {
+	return &Encoder{telemetry: Telemetry{State: StateReady}, product: product}
+}
+
+// Telemetry returns the latest telemetry.
+func (e *Encoder) Telemetry() Telemetry {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.telemetry
+}
+
+// Product returns the product information.
+func (e *Encoder) Product() Product {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.product
+}
+
+// Page returns the given data page encoded from the latest telemetry, and false for a page this package does not
+// encode.
+func (e *Encoder) Page(number byte) ([8]byte, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	switch number {
+	case PageGeneralFEData:
+		return GeneralFEData(e.telemetry), true
+	case PageRowerData:
+		return RowerData(e.telemetry), true
+	case PageTrainerData:
+		return TrainerData(e.telemetry), true
+	case PageManufacturerInformation:
+		return ManufacturerInformation(e.product), true
+	case PageProductInformation:
+		return ProductInformation(e.product), true
+	default:
+		return [8]byte{}, false
+	}
+}
+
+// Observe feeds an event to the encoder. Events of other types are ignored, so the encoder can be fed straight from
+// EventStream or a Poller.
+//
+// Every power response counts as a power reading of the trainer page. Snapshots carry no sign of a new power
+// response, so from them a reading is counted for every new stroke instead, which matches the stroke triggers of
+// pm5.DefaultSchedule.
+func (e *Encoder) Observe(event any) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	t := &e.telemetry
+	switch ev := event.(type) {
+	case pm5.GetStatusResponse:
+		t.State = machineState(ev.StateMachineState)
+	case pm5.GetWorkTimeResponse:
+		t.ElapsedTime = ev.WorkTime
+	case pm5.GetTWorkResponse:
+		t.ElapsedTime = ev.WorkTime
+	case pm5.GetWorkDistanceResponse:
+		t.Distance = ev.Meters
+	case pm5.GetHorizontalResponse:
+		t.Distance = ev.Meters
+	case pm5.GetStroke500mPaceResponse:
+		t.Speed = speed(ev.Per500m.Seconds())
+	case pm5.GetPaceResponse:
+		t.Speed = speed(ev.Per500m.Seconds())
+	case pm5.GetStrokeRateResponse:
+		t.StrokeRate = ev.StrokesPerMinute
+	case pm5.GetCadenceResponse:
+		t.StrokeRate = ev.StrokesPerMinute
+	case pm5.GetStrokeStatsResponse:
+		t.Strokes = ev.DriveCounter
+	case pm5.GetPowerResponse:
+		if ev.UnitsSpecifier == pm5.PowerUnitsWatts {
+			t.Power = ev.StrokeWatts
+			t.powerReading()
+		}
+	case pm5.GetHRCurResponse:
+		t.HeartRate = heartRate(ev)
+	case pm5.GetSerialResponse:
+		if serial, err := strconv.ParseUint(ev.SerialNumber, 10, 32); err == nil {
+			e.product.SerialNumber = uint32(serial)
+		}
+	case pm5.Snapshot:
+		newStroke := ev.Strokes != t.Strokes
+		t.State = machineState(ev.Status.StateMachineState)
+		t.ElapsedTime = ev.WorkTime
+		t.Distance = ev.Meters
+		t.Speed = speed(ev.Pace.Seconds())
+		t.StrokeRate = ev.StrokeRate
+		t.Strokes = ev.Strokes
+		t.Power = ev.Power
+		t.HeartRate = heartRate(pm5.GetHRCurResponse{BeatsPerMinute: ev.HeartRate})
+		if newStroke {
+			t.powerReading()
+		}
+	}
+}
+
+// powerReading adds the current power to the readings of the trainer page.
+func (t *Telemetry) powerReading() {
+	t.PowerEvents = (t.PowerEvents + 1) % 256
+	t.AccumulatedPower = (t.AccumulatedPower + t.Power) % 65536
+}
+
+// machineState returns the FE state of a CSAFE machine state.
+func machineState(s byte) State {
+	switch s {
+	case pm5.MachineStateReady, pm5.MachineStateIdle, pm5.MachineStateHaveID:
+		return StateReady
+	case pm5.MachineStateInUse, pm5.MachineStateManual:
+		return StateInUse
+	case pm5.MachineStatePause, pm5.MachineStateFinish:
+		return StateFinished
+	default:
+		return StateAsleep
+	}
+}
+
+// speed returns the speed in m/s of a pace in seconds per 500 m.
+func speed(per500m float64) float64 {
+	if per500m <= 0 {
+		return 0
+	}
+	return 500 / per500m
+}
+
+func heartRate(hr pm5.GetHRCurResponse) int {
+	if !hr.Connected() {
+		return 0
+	}
+	return hr.BeatsPerMinute
+}
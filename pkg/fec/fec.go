@@ -0,0 +1,73 @@
+// Package fec encodes rower data as the data pages of the ANT+ Fitness Equipment Control (FE-C) profile, for gym
+// head units and watches that speak ANT+ rather than Bluetooth.
+//
+// The pages are 8 bytes each, ready to be sent as broadcast data on an ANT channel with device type 17 and a channel
+// period of 8192 (4 Hz). Opening the channel is left to the ANT radio driver; a Scheduler writes the pages to it in
+// the FE-C transmission pattern.
+package fec
+
+import (
+	"time"
+)
+
+// Data page numbers.
+const (
+	PageGeneralFEData           byte = 0x10 // 16
+	PageRowerData               byte = 0x16 // 22
+	PageTrainerData             byte = 0x19 // 25
+	PageManufacturerInformation byte = 0x50 // 80
+	PageProductInformation      byte = 0x51 // 81
+)
+
+// EquipmentTypeRower is the equipment type of the general FE data page for a rower.
+const EquipmentTypeRower byte = 22
+
+// State is the FE state reported in the last byte of the FE pages.
+type State byte
+
+const (
+	StateAsleep   State = 1
+	StateReady    State = 2
+	StateInUse    State = 3
+	StateFinished State = 4 // Finished or paused
+)
+
+// Telemetry is the rower data the FE pages are encoded from.
+type Telemetry struct {
+	ElapsedTime time.Duration
+	Distance    float64 // Meters
+	Speed       float64 // Meters per second
+	StrokeRate  int
+	Strokes     int
+	Power       int // Watts
+	HeartRate   int // Zero without a heart rate belt
+	State       State
+
+	// The trainer page reports power as a running sum of readings, so that receivers can average it between the
+	// pages they happen to receive.
+	PowerEvents      int
+	AccumulatedPower int // Watts
+}
+
+// ManufacturerConcept2 is the ANT+ manufacturer ID of Concept2.
+const ManufacturerConcept2 uint16 = 40
+
+// Product describes the equipment in the manufacturer and product information pages.
+type Product struct {
+	HardwareRevision             byte
+	Manufacturer                 uint16
+	Model                        uint16
+	SoftwareRevision             byte
+	SoftwareRevisionSupplemental byte   // 0xFF when not used
+	SerialNumber                 uint32 // 0xFFFFFFFF when unknown
+}
+
+// DefaultProduct describes a Concept2 PM5 of unknown serial number.
+var DefaultProduct = Product{
+	HardwareRevision:             1,
+	Manufacturer:                 ManufacturerConcept2,
+	Model:                        5,
+	SoftwareRevision:             1,
+	SoftwareRevisionSupplemental: 0xFF,
+	SerialNumber:                 0xFFFFFFFF,
+}
@@ -0,0 +1,107 @@
+package fec
+
+import (
+	"encoding/binary"
+	"math"
+	"time"
+)
+
+// Capabilities of the general FE data page.
+const (
+	capabilityHeartRateMonitor byte = 0x01 // Heart rate from a heart rate monitor
+	capabilityDistance         byte = 0x04 // Distance traveled enabled
+	capabilityVirtualSpeed     byte = 0x08 // Speed derived from the flywheel rather than measured
+)
+
+// capabilityStrokeCount marks the stroke count of the rower data page as transmitted.
+const capabilityStrokeCount byte = 0x01
+
+// Values marking a field of a page as invalid.
+const (
+	invalidUint8  = 0xFF
+	invalidUint12 = 0xFFF
+	invalidUint16 = 0xFFFF
+)
+
+// GeneralFEData returns page 16, with the elapsed time, distance, speed and heart rate. Elapsed time and distance
+// roll over every 64 s and 256 m.
+func GeneralFEData(t Telemetry) [8]byte {
+	capabilities := capabilityDistance | capabilityVirtualSpeed
+	hr := byte(invalidUint8)
+	if t.HeartRate > 0 {
+		capabilities |= capabilityHeartRateMonitor
+		hr = byte(clamp(t.HeartRate, invalidUint8-1))
+	}
+
+	b := [8]byte{
+		0: PageGeneralFEData,
+		1: EquipmentTypeRower,
+		2: byte(t.ElapsedTime / (250 * time.Millisecond)),
+		3: byte(int(t.Distance)),
+		6: hr,
+		7: capabilities | byte(t.State)<<4,
+	}
+	binary.LittleEndian.PutUint16(b[4:], uint16(clamp(int(math.Round(t.Speed*1000)), invalidUint16-1)))
+	return b
+}
+
+// RowerData returns page 22, with the stroke count, stroke rate and power. The stroke count rolls over every 256
+// strokes.
+func RowerData(t Telemetry) [8]byte {
+	b := [8]byte{
+		0: PageRowerData,
+		1: 0xFF,
+		2: 0xFF,
+		3: byte(t.Strokes),
+		4: byte(clamp(t.StrokeRate, invalidUint8-1)),
+		7: capabilityStrokeCount | byte(t.State)<<4,
+	}
+	binary.LittleEndian.PutUint16(b[5:], uint16(clamp(t.Power, invalidUint16-1)))
+	return b
+}
+
+// TrainerData returns page 25, with the stroke rate as cadence and the power readings. Receivers that take power
+// only from trainers read it here. The event count and accumulated power roll over at 256 and 65536.
+func TrainerData(t Telemetry) [8]byte {
+	power := uint16(clamp(t.Power, invalidUint12-1))
+	b := [8]byte{
+		0: PageTrainerData,
+		1: byte(t.PowerEvents),
+		2: byte(clamp(t.StrokeRate, invalidUint8-1)),
+		5: byte(power),
+		6: byte(power >> 8), // The trainer status bits above stay clear
+		7: byte(t.State) << 4,
+	}
+	binary.LittleEndian.PutUint16(b[3:], uint16(t.AccumulatedPower))
+	return b
+}
+
+// ManufacturerInformation returns common page 80.
+func ManufacturerInformation(p Product) [8]byte {
+	b := [8]byte{
+		0: PageManufacturerInformation,
+		1: 0xFF,
+		2: 0xFF,
+		3: p.HardwareRevision,
+	}
+	binary.LittleEndian.PutUint16(b[4:], p.Manufacturer)
+	binary.LittleEndian.PutUint16(b[6:], p.Model)
+	return b
+}
+
+// ProductInformation returns common page 81.
+func ProductInformation(p Product) [8]byte {
+	b := [8]byte{
+		0: PageProductInformation,
+		1: 0xFF,
+		2: p.SoftwareRevisionSupplemental,
+		3: p.SoftwareRevision,
+	}
+	binary.LittleEndian.PutUint32(b[4:], p.SerialNumber)
+	return b
+}
+
+// clamp limits v to the range of a field whose largest valid value is hi.
+func clamp(v, hi int) int {
+	return min(max(v, 0), hi)
+}
@@ -0,0 +1,80 @@
+package fec
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPages(t *testing.T) {
+	rowing := Telemetry{
+		ElapsedTime:      75500 * time.Millisecond,
+		Distance:         1300.7,
+		Speed:            500.0 / 120,
+		StrokeRate:       24,
+		Strokes:          300,
+		Power:            203,
+		HeartRate:        150,
+		State:            StateInUse,
+		PowerEvents:      258,
+		AccumulatedPower: 70000,
+	}
+	// Out of range values are clamped below the invalid markers.
+	idle := Telemetry{StrokeRate: 300, Power: 5000, State: StateReady}
+
+	product := DefaultProduct
+	product.SerialNumber = 430000000
+
+	tests := []struct {
+		name string
+		got  [8]byte
+		want [8]byte
+	}{
+		{
+			name: "general FE data",
+			got:  GeneralFEData(rowing),
+			// 302 quarter seconds and 1300 m rolled over, 4.167 m/s, 150 bpm, in use
+			want: [8]byte{0x10, 0x16, 0x2E, 0x14, 0x47, 0x10, 0x96, 0x3D},
+		},
+		{
+			name: "general FE data without heart rate",
+			got:  GeneralFEData(idle),
+			want: [8]byte{0x10, 0x16, 0x00, 0x00, 0x00, 0x00, 0xFF, 0x2C},
+		},
+		{
+			name: "rower data",
+			got:  RowerData(rowing),
+			want: [8]byte{0x16, 0xFF, 0xFF, 0x2C, 0x18, 0xCB, 0x00, 0x31},
+		},
+		{
+			name: "rower data clamped",
+			got:  RowerData(idle),
+			want: [8]byte{0x16, 0xFF, 0xFF, 0x00, 0xFE, 0x88, 0x13, 0x21},
+		},
+		{
+			name: "trainer data",
+			got:  TrainerData(rowing),
+			want: [8]byte{0x19, 0x02, 0x18, 0x70, 0x11, 0xCB, 0x00, 0x30},
+		},
+		{
+			name: "trainer data clamped",
+			got:  TrainerData(idle),
+			want: [8]byte{0x19, 0x00, 0xFE, 0x00, 0x00, 0xFE, 0x0F, 0x20},
+		},
+		{
+			name: "manufacturer information",
+			got:  ManufacturerInformation(product),
+			want: [8]byte{0x50, 0xFF, 0xFF, 0x01, 0x28, 0x00, 0x05, 0x00},
+		},
+		{
+			name: "product information",
+			got:  ProductInformation(product),
+			want: [8]byte{0x51, 0xFF, 0xFF, 0x01, 0x80, 0x47, 0xA1, 0x19},
+		},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got % X, want % X", tt.name, tt.got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package fec
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// DefaultPeriod is the message period of an FE-C channel, 8192/32768 s.
+const DefaultPeriod = 250 * time.Millisecond
+
+// dataPattern is the order of the data pages; each is sent twice in a row.
+var dataPattern = []byte{PageGeneralFEData, PageRowerData, PageGeneralFEData, PageTrainerData}
+
+// commonInterval is the number of data pages between two common pages.
+const commonInterval = 64
+
+// Scheduler writes the pages of an Encoder to a sink in the FE-C transmission pattern: the general FE data page
+// alternates with the rower and trainer pages, each sent twice in a row, and after every 64 data pages the
+// manufacturer and product information pages take turns being sent twice:
+//
+//	16 16 22 22 16 16 25 25 ... 16 16 25 25 80 80 16 16 22 22 ... 81 81 ...
+//
+// Every page is written to the sink with a single 8-byte Write, so the sink can hand it to an ANT radio as one
+// broadcast message.
+type Scheduler struct {
+	enc  *Encoder
+	sink io.Writer
+
+	// Period is the time between two pages written by Run. It defaults to DefaultPeriod and must be set before Run.
+	Period time.Duration
+
+	data   int // Data pages sent since the last common pages
+	common int // Common pages sent
+}
+
+// NewScheduler returns a Scheduler writing the pages of enc to sink. Call Run to start writing.
+func NewScheduler(enc *Encoder, sink io.Writer) *Scheduler {
+	return &Scheduler{enc: enc, sink: sink, Period: DefaultPeriod}
+}
+
+// Next returns the next page of the pattern, encoded from the latest telemetry.
+func (s *Scheduler) Next() [8]byte {
+	var number byte
+	if s.data == commonInterval {
+		number = PageManufacturerInformation
+		if s.common/2%2 == 1 {
+			number = PageProductInformation
+		}
+		s.common++
+		if s.common%2 == 0 {
+			s.data = 0
+		}
+	} else {
+		number = dataPattern[s.data/2%len(dataPattern)]
+		s.data++
+	}
+
+	page, _ := s.enc.Page(number)
+	return page
+}
+
+// Run writes a page every Period until ctx is done or the sink fails.
+func (s *Scheduler) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.Period)
+	defer ticker.Stop()
+
+	for {
+		page := s.Next()
+		if _, err := s.sink.Write(page[:]); err != nil {
+			return fmt.Errorf("fec: writing page %d: %w", page[0], err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
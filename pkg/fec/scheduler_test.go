@@ -0,0 +1,140 @@
+package fec
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/seagrayinc/gorow/pkg/pm5"
+)
+
+func TestEncoderObserve(t *testing.T) {
+	enc := NewEncoder(DefaultProduct)
+	for _, e := range []any{
+		pm5.GetSerialResponse{SerialNumber: "430000000"},
+		pm5.GetStatusResponse{StateMachineState: pm5.MachineStateInUse},
+		pm5.GetWorkTimeResponse{WorkTime: time.Minute},
+		pm5.GetWorkDistanceResponse{Meters: 250},
+		pm5.GetStroke500mPaceResponse{Per500m: 2 * time.Minute},
+		pm5.GetStrokeRateResponse{StrokesPerMinute: 24},
+		pm5.GetStrokeStatsResponse{DriveCounter: 25},
+		pm5.GetPowerResponse{StrokeWatts: 200, UnitsSpecifier: pm5.PowerUnitsWatts},
+		pm5.GetPowerResponse{StrokeWatts: 210, UnitsSpecifier: pm5.PowerUnitsWatts},
+		pm5.GetHRCurResponse{BeatsPerMinute: 255},
+	} {
+		enc.Observe(e)
+	}
+
+	want := Telemetry{
+		ElapsedTime:      time.Minute,
+		Distance:         250,
+		Speed:            500.0 / 120,
+		StrokeRate:       24,
+		Strokes:          25,
+		Power:            210,
+		State:            StateInUse,
+		PowerEvents:      2,
+		AccumulatedPower: 410,
+	}
+	if got := enc.Telemetry(); got != want {
+		t.Errorf("telemetry mismatch:\ngot:  %+v\nwant: %+v", got, want)
+	}
+	if got := enc.Product().SerialNumber; got != 430000000 {
+		t.Errorf("serial number: got %d, want 430000000", got)
+	}
+
+	// A snapshot counts a power reading only for a new stroke.
+	snapshot := pm5.Snapshot{
+		Status:  pm5.GetStatusResponse{StateMachineState: pm5.MachineStateFinish},
+		Strokes: 26,
+		Power:   190,
+	}
+	enc.Observe(snapshot)
+	enc.Observe(snapshot)
+	if got := enc.Telemetry(); got.PowerEvents != 3 || got.AccumulatedPower != 600 || got.State != StateFinished {
+		t.Errorf("telemetry after snapshots: %+v", got)
+	}
+}
+
+func TestSchedulerPattern(t *testing.T) {
+	s := NewScheduler(NewEncoder(DefaultProduct), nil)
+	var pages []byte
+	for range 2*(commonInterval+2) + 4 {
+		page := s.Next()
+		pages = append(pages, page[0])
+	}
+
+	checks := []struct {
+		at   int
+		want []byte
+	}{
+		{0, []byte{16, 16, 22, 22, 16, 16, 25, 25, 16, 16}},
+		{60, []byte{16, 16, 25, 25, 80, 80, 16, 16, 22, 22}},
+		{126, []byte{16, 16, 25, 25, 81, 81, 16, 16, 22, 22}},
+	}
+	for _, c := range checks {
+		if got := pages[c.at : c.at+len(c.want)]; !slices.Equal(got, c.want) {
+			t.Errorf("pages from %d: got %v, want %v", c.at, got, c.want)
+		}
+	}
+}
+
+// pageSink records the pages written to it, failing with err when set.
+type pageSink struct {
+	mu    sync.Mutex
+	pages [][]byte
+	err   error
+}
+
+func (s *pageSink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return 0, s.err
+	}
+	s.pages = append(s.pages, append([]byte(nil), p...))
+	return len(p), nil
+}
+
+func (s *pageSink) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.pages)
+}
+
+func TestSchedulerRun(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	enc := NewEncoder(DefaultProduct)
+	enc.Observe(pm5.GetStrokeRateResponse{StrokesPerMinute: 24})
+	sink := &pageSink{}
+	s := NewScheduler(enc, sink)
+	s.Period = time.Millisecond
+
+	runCtx, stop := context.WithCancel(ctx)
+	done := make(chan error)
+	go func() { done <- s.Run(runCtx) }()
+	for sink.count() < 4 && ctx.Err() == nil {
+		time.Sleep(time.Millisecond)
+	}
+	stop()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Run: got %v, want context.Canceled", err)
+	}
+
+	want := [][8]byte{GeneralFEData(enc.Telemetry()), RowerData(enc.Telemetry())}
+	for i, w := range []int{0, 2} {
+		if got := sink.pages[w]; !slices.Equal(got, want[i][:]) {
+			t.Errorf("page %d: got % X, want % X", w, got, want[i])
+		}
+	}
+
+	sink.err = errors.New("radio gone")
+	if err := s.Run(ctx); !errors.Is(err, sink.err) {
+		t.Errorf("Run with a failing sink: got %v, want %v", err, sink.err)
+	}
+}
@@ -0,0 +1,246 @@
+package ftms
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/seagrayinc/gorow/pkg/pm5"
+)
+
+// rowerFlags are the Rower Data fields a Bridge always reports. The heart rate is added while a belt is connected.
+const rowerFlags = TotalDistancePresent | InstantaneousPacePresent | AveragePacePresent | InstantaneousPowerPresent |
+	AveragePowerPresent | ExpendedEnergyPresent | ElapsedTimePresent
+
+// Bridge presents a PM as an FTMS rower. It follows the PM's events into a RowerData record and Fitness Machine
+// Status changes, and turns Control Point requests into PM commands:
+//
+//	b := ftms.NewBridge(p)
+//	for e := range p.EventStream() {
+//		for _, status := range b.Observe(time.Now(), e) {
+//			// Notify status on FitnessMachineStatusUUID
+//		}
+//		// Notify b.RowerData().Split(20) on RowerDataUUID
+//	}
+//
+// A Bridge is safe for concurrent use, so Control Point writes can be handled from the Bluetooth stack's callbacks.
+type Bridge struct {
+	// request sends a command in a frame of its own and waits for the PM to accept it.
+	request func(ctx context.Context, command pm5.Command) error
+
+	mu           sync.Mutex
+	session      *pm5.Session
+	data         RowerData
+	machineState byte
+	controlled   bool // A client holds control of the machine
+}
+
+// NewBridge returns a Bridge sending the commands of Control Point requests to p. Every command is sent with
+// pm5.Query, so a request only succeeds once the PM has accepted its commands.
+func NewBridge(p *pm5.PM5) *Bridge {
+	return newBridge(func(ctx context.Context, command pm5.Command) error {
+		_, err := pm5.Query[pm5.GetStatusResponse](ctx, p, command)
+		return err
+	})
+}
+
+func newBridge(request func(ctx context.Context, command pm5.Command) error) *Bridge {
+	return &Bridge{
+		request:      request,
+		session:      pm5.NewSession(),
+		data:         RowerData{Flags: rowerFlags},
+		machineState: pm5.MachineStateReady,
+	}
+}
+
+// RowerData returns the latest Rower Data record.
+func (b *Bridge) RowerData() RowerData {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.data
+}
+
+// Observe feeds an event received at the given time to the bridge and returns the Fitness Machine Status payloads it
+// causes. Events of other types are ignored, so the bridge can be fed straight from EventStream or a Poller.
+func (b *Bridge) Observe(at time.Time, event any) [][]byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	d := &b.data
+	switch e := event.(type) {
+	case pm5.GetStatusResponse:
+		b.machineState = e.StateMachineState
+	case pm5.GetStrokeRateResponse:
+		d.StrokeRate = float64(e.StrokesPerMinute)
+	case pm5.GetCadenceResponse:
+		d.StrokeRate = float64(e.StrokesPerMinute)
+	case pm5.GetStrokeStatsResponse:
+		d.StrokeCount = e.DriveCounter
+	case pm5.GetWorkDistanceResponse:
+		d.TotalDistance = int(e.Meters)
+	case pm5.GetHorizontalResponse:
+		d.TotalDistance = int(e.Meters)
+	case pm5.GetStroke500mPaceResponse:
+		d.Pace = e.Per500m
+	case pm5.GetPaceResponse:
+		d.Pace = e.Per500m
+	case pm5.GetPowerResponse:
+		if e.UnitsSpecifier == pm5.PowerUnitsWatts {
+			d.Power = e.StrokeWatts
+		}
+	case pm5.GetCaloriesResponse:
+		d.TotalEnergy = e.Calories
+	case pm5.GetHRCurResponse:
+		b.heartRate(e)
+	case pm5.GetWorkTimeResponse:
+		d.ElapsedTime = e.WorkTime
+	case pm5.GetTWorkResponse:
+		d.ElapsedTime = e.WorkTime
+	case pm5.Snapshot:
+		b.machineState = e.Status.StateMachineState
+		d.StrokeRate = float64(e.StrokeRate)
+		d.StrokeCount = e.Strokes
+		d.TotalDistance = int(e.Meters)
+		d.Pace = e.Pace
+		d.Power = e.Power
+		d.TotalEnergy = e.Calories
+		d.ElapsedTime = e.WorkTime
+		b.heartRate(pm5.GetHRCurResponse{BeatsPerMinute: e.HeartRate})
+	}
+	b.derive()
+
+	var statuses [][]byte
+	for _, e := range b.session.Observe(at, event) {
+		switch e.Type {
+		case pm5.WorkoutStarted:
+			statuses = append(statuses, []byte{StatusStartedOrResumed})
+		case pm5.WorkoutFinished, pm5.WorkoutTerminated:
+			statuses = append(statuses, []byte{StatusStoppedOrPaused, StopParam})
+		case pm5.Rearmed:
+			statuses = append(statuses, []byte{StatusReset})
+		}
+	}
+	return statuses
+}
+
+func (b *Bridge) heartRate(hr pm5.GetHRCurResponse) {
+	b.data.HeartRate = 0
+	b.data.Flags &^= HeartRatePresent
+	if hr.Connected() {
+		b.data.HeartRate = hr.BeatsPerMinute
+		b.data.Flags |= HeartRatePresent
+	}
+}
+
+// derive updates the averages and the energy rate from the reported metrics.
+func (b *Bridge) derive() {
+	d := &b.data
+
+	d.AveragePace, d.AveragePower = 0, 0
+	if d.TotalDistance > 0 {
+		d.AveragePace = d.ElapsedTime * 500 / time.Duration(d.TotalDistance)
+		d.AveragePower = int(math.Round(pm5.PaceWatts(d.AveragePace)))
+	}
+
+	// Concept2 estimate of the energy spent: 4 kcal per Wh at 86.04% efficiency, plus 300 kcal/h at rest.
+	d.EnergyPerHour = int(math.Round(float64(d.Power)*4*0.8604)) + 300
+	d.EnergyPerMinute = int(math.Round(float64(d.EnergyPerHour) / 60))
+}
+
+// HandleControlPoint handles a write to the Control Point characteristic. It returns the response to indicate on the
+// Control Point, and the Fitness Machine Status payload to notify, nil when the request changes no status or only
+// changes what the PM will report on its own. An empty request returns nil payloads.
+//
+// Requests other than OpRequestControl are refused until a client has requested control, as the specification
+// requires; OpReset revokes it. Requests the PM rejects or does not answer in time fail with ResultOperationFailed.
+// OpSetTargetPower programs the equivalent target pace, which the PM only takes before a workout starts, so it fails
+// with ResultOperationFailed while the machine is in use.
+func (b *Bridge) HandleControlPoint(ctx context.Context, request []byte) (response, status []byte) {
+	if len(request) == 0 {
+		return nil, nil
+	}
+	op, params := request[0], request[1:]
+	result, status := b.control(ctx, op, params)
+	return []byte{OpResponseCode, op, result}, status
+}
+
+func (b *Bridge) control(ctx context.Context, op byte, params []byte) (byte, []byte) {
+	b.mu.Lock()
+	if op == OpRequestControl {
+		b.controlled = true
+	}
+	controlled, machineState := b.controlled, b.machineState
+	b.mu.Unlock()
+
+	// Commands are sent without holding the lock, so that events keep flowing while the PM answers.
+	if op == OpRequestControl {
+		return ResultSuccess, nil
+	}
+	if !controlled {
+		return ResultControlNotPermitted, nil
+	}
+
+	switch op {
+	case OpReset:
+		if err := b.request(ctx, pm5.Reset()); err != nil {
+			return ResultOperationFailed, nil
+		}
+		b.mu.Lock()
+		b.controlled = false
+		b.session = pm5.NewSession()
+		b.data = RowerData{Flags: rowerFlags}
+		b.machineState = pm5.MachineStateReady
+		b.mu.Unlock()
+		return ResultSuccess, []byte{StatusReset}
+
+	case OpStartOrResume:
+		if machineState == pm5.MachineStateInUse {
+			return ResultSuccess, nil
+		}
+		// The PM only goes in use from the have ID state.
+		for _, c := range []pm5.Command{pm5.GoIdle(), pm5.GoHaveID(), pm5.GoInUse()} {
+			if err := b.request(ctx, c); err != nil {
+				return ResultOperationFailed, nil
+			}
+		}
+		return ResultSuccess, nil
+
+	case OpStopOrPause:
+		if len(params) != 1 {
+			return ResultInvalidParameter, nil
+		}
+		switch params[0] {
+		case StopParam:
+			if err := b.request(ctx, pm5.GoFinished()); err != nil {
+				return ResultOperationFailed, nil
+			}
+			return ResultSuccess, nil
+		case PauseParam:
+			// The PM pauses on its own when rowing stops; it cannot be told to.
+			return ResultOperationFailed, nil
+		default:
+			return ResultInvalidParameter, nil
+		}
+
+	case OpSetTargetPower:
+		if len(params) != 2 {
+			return ResultInvalidParameter, nil
+		}
+		watts := int16(binary.LittleEndian.Uint16(params))
+		if watts <= 0 {
+			return ResultInvalidParameter, nil
+		}
+		if machineState == pm5.MachineStateInUse {
+			return ResultOperationFailed, nil
+		}
+		if err := b.request(ctx, pm5.SetTargetPaceTime(pm5.WattsPace(float64(watts)).Round(10*time.Millisecond))); err != nil {
+			return ResultOperationFailed, nil
+		}
+		return ResultSuccess, append([]byte{StatusTargetPowerChanged}, params...)
+
+	default:
+		return ResultOpCodeNotSupported, nil
+	}
+}
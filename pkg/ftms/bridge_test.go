@@ -0,0 +1,227 @@
+package ftms
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/seagrayinc/gorow/pkg/pm5"
+)
+
+// fakePM records the commands a Bridge sends, failing with err when set.
+type fakePM struct {
+	sent []pm5.Command
+	err  error
+}
+
+func (pm *fakePM) request(_ context.Context, command pm5.Command) error {
+	if pm.err != nil {
+		return pm.err
+	}
+	pm.sent = append(pm.sent, command)
+	return nil
+}
+
+func TestBridgeObserve(t *testing.T) {
+	b := newBridge((&fakePM{}).request)
+	start := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	at := func(s int) time.Time { return start.Add(time.Duration(s) * time.Second) }
+
+	if got := b.Observe(at(0), pm5.GetWorkoutStateResponse{WorkoutState: 1}); !slices.EqualFunc(got, [][]byte{{StatusStartedOrResumed}}, bytes.Equal) {
+		t.Errorf("start status: got % X", got)
+	}
+
+	for _, e := range []any{
+		pm5.GetWorkTimeResponse{WorkTime: 2 * time.Minute},
+		pm5.GetWorkDistanceResponse{Meters: 500.4},
+		pm5.GetStroke500mPaceResponse{Per500m: 118 * time.Second},
+		pm5.GetStrokeRateResponse{StrokesPerMinute: 24},
+		pm5.GetStrokeStatsResponse{DriveCounter: 60},
+		pm5.GetPowerResponse{StrokeWatts: 215, UnitsSpecifier: pm5.PowerUnitsWatts},
+		pm5.GetCaloriesResponse{Calories: 30},
+		pm5.GetHRCurResponse{BeatsPerMinute: 150},
+	} {
+		if got := b.Observe(at(120), e); got != nil {
+			t.Errorf("%T: unexpected status % X", e, got)
+		}
+	}
+
+	want := []byte{
+		0x7C, 0x0B,
+		0x30, 0x3C, 0x00, // 24 spm, 60 strokes
+		0xF4, 0x01, 0x00, // 500 m
+		0x76, 0x00, // 118 s
+		0x78, 0x00, // 120 s
+		0xD7, 0x00, // 215 W
+		0xCB, 0x00, // 203 W at 2:00/500m
+		0x1E, 0x00, 0x10, 0x04, 0x11, // 30 kcal, 1040 kcal/h, 17 kcal/min
+		0x96,       // 150 bpm
+		0x78, 0x00, // 120 s
+	}
+	if got := b.RowerData().Encode(); !bytes.Equal(got, want) {
+		t.Errorf("rower data mismatch:\ngot:  % X\nwant: % X", got, want)
+	}
+
+	// Without a belt the heart rate is left out.
+	b.Observe(at(121), pm5.GetHRCurResponse{BeatsPerMinute: 255})
+	if got := b.RowerData(); got.Flags&HeartRatePresent != 0 || got.HeartRate != 0 {
+		t.Errorf("heart rate reported without a belt: %+v", got)
+	}
+
+	if got := b.Observe(at(240), pm5.GetWorkoutStateResponse{WorkoutState: 10}); !slices.EqualFunc(got, [][]byte{{StatusStoppedOrPaused, StopParam}}, bytes.Equal) {
+		t.Errorf("stop status: got % X", got)
+	}
+	if got := b.Observe(at(300), pm5.GetWorkoutStateResponse{WorkoutState: 0}); !slices.EqualFunc(got, [][]byte{{StatusReset}}, bytes.Equal) {
+		t.Errorf("reset status: got % X", got)
+	}
+}
+
+func TestBridgeControlPoint(t *testing.T) {
+	ctx := context.Background()
+	pm := &fakePM{}
+	b := newBridge(pm.request)
+
+	steps := []struct {
+		name     string
+		event    any // Observed before the request
+		request  []byte
+		response []byte
+		status   []byte
+		sent     []pm5.Command
+	}{
+		{
+			name:     "start without control",
+			request:  []byte{OpStartOrResume},
+			response: []byte{0x80, 0x07, 0x05},
+		},
+		{
+			name:     "request control",
+			request:  []byte{OpRequestControl},
+			response: []byte{0x80, 0x00, 0x01},
+		},
+		{
+			name:     "target power",
+			request:  []byte{OpSetTargetPower, 0xCB, 0x00},
+			response: []byte{0x80, 0x05, 0x01},
+			status:   []byte{0x08, 0xCB, 0x00},
+			sent:     []pm5.Command{pm5.SetTargetPaceTime(119910 * time.Millisecond)},
+		},
+		{
+			name:     "negative target power",
+			request:  []byte{OpSetTargetPower, 0x00, 0x80},
+			response: []byte{0x80, 0x05, 0x03},
+		},
+		{
+			name:     "start",
+			request:  []byte{OpStartOrResume},
+			response: []byte{0x80, 0x07, 0x01},
+			sent:     []pm5.Command{pm5.GoIdle(), pm5.GoHaveID(), pm5.GoInUse()},
+		},
+		{
+			name:     "start while in use",
+			event:    pm5.GetStatusResponse{StateMachineState: pm5.MachineStateInUse},
+			request:  []byte{OpStartOrResume},
+			response: []byte{0x80, 0x07, 0x01},
+		},
+		{
+			name:     "target power while in use",
+			request:  []byte{OpSetTargetPower, 0xCB, 0x00},
+			response: []byte{0x80, 0x05, 0x04},
+		},
+		{
+			name:     "pause",
+			request:  []byte{OpStopOrPause, PauseParam},
+			response: []byte{0x80, 0x08, 0x04},
+		},
+		{
+			name:     "stop without parameter",
+			request:  []byte{OpStopOrPause},
+			response: []byte{0x80, 0x08, 0x03},
+		},
+		{
+			name:     "stop",
+			request:  []byte{OpStopOrPause, StopParam},
+			response: []byte{0x80, 0x08, 0x01},
+			sent:     []pm5.Command{pm5.GoFinished()},
+		},
+		{
+			name:     "unsupported",
+			request:  []byte{0x02},
+			response: []byte{0x80, 0x02, 0x02},
+		},
+		{
+			name:     "reset",
+			request:  []byte{OpReset},
+			response: []byte{0x80, 0x01, 0x01},
+			status:   []byte{StatusReset},
+			sent:     []pm5.Command{pm5.Reset()},
+		},
+		{
+			name:     "start after reset",
+			request:  []byte{OpStartOrResume},
+			response: []byte{0x80, 0x07, 0x05},
+		},
+		{
+			name:    "empty",
+			request: []byte{},
+		},
+	}
+
+	for _, step := range steps {
+		pm.sent = nil
+		if step.event != nil {
+			b.Observe(time.Now(), step.event)
+		}
+		response, status := b.HandleControlPoint(ctx, step.request)
+		if !bytes.Equal(response, step.response) {
+			t.Errorf("%s: response: got % X, want % X", step.name, response, step.response)
+		}
+		if !bytes.Equal(status, step.status) {
+			t.Errorf("%s: status: got % X, want % X", step.name, status, step.status)
+		}
+		if !reflect.DeepEqual(pm.sent, step.sent) {
+			t.Errorf("%s: sent % X, want % X", step.name, pm.sent, step.sent)
+		}
+	}
+
+	// A command the PM rejects or does not answer fails the request.
+	b.HandleControlPoint(ctx, []byte{OpRequestControl})
+	for _, err := range []error{&pm5.FrameStatusError{Status: pm5.FrameStatusReject, Attempts: 1}, pm5.ErrTimeout} {
+		pm.err = err
+		response, status := b.HandleControlPoint(ctx, []byte{OpStopOrPause, StopParam})
+		if want := []byte{0x80, 0x08, 0x04}; !bytes.Equal(response, want) || status != nil {
+			t.Errorf("%v: got response % X and status % X, want % X", err, response, status, want)
+		}
+	}
+}
+
+func TestBridgeEmulator(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	p, err := pm5.Open(ctx, pm5.WithDevice(pm5.NewEmulator(pm5.EmulatorConfig{Manual: true})))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer p.Close()
+	b := NewBridge(p)
+
+	for _, step := range []struct {
+		name     string
+		request  []byte
+		response []byte
+	}{
+		{name: "request control", request: []byte{OpRequestControl}, response: []byte{0x80, 0x00, 0x01}},
+		{name: "stop before start", request: []byte{OpStopOrPause, StopParam}, response: []byte{0x80, 0x08, 0x04}},
+		{name: "target power", request: []byte{OpSetTargetPower, 0xCB, 0x00}, response: []byte{0x80, 0x05, 0x01}},
+		{name: "start", request: []byte{OpStartOrResume}, response: []byte{0x80, 0x07, 0x01}},
+		{name: "stop", request: []byte{OpStopOrPause, StopParam}, response: []byte{0x80, 0x08, 0x01}},
+	} {
+		if response, _ := b.HandleControlPoint(ctx, step.request); !bytes.Equal(response, step.response) {
+			t.Errorf("%s: response: got % X, want % X", step.name, response, step.response)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// Package ftms encodes rower data in the Bluetooth Fitness Machine Service (FTMS) format defined at
+// https://www.bluetooth.com/specifications/specs/fitness-machine-service-1-0/
+//
+// It produces the payloads of the FTMS characteristics and handles Control Point requests; advertising the service
+// is left to the Bluetooth stack of the platform. Bridge turns live pkg/pm5 events into these payloads.
+package ftms
+
+import (
+	"encoding/binary"
+)
+
+// 16-bit UUIDs of the service and its characteristics.
+const (
+	ServiceUUID              uint16 = 0x1826
+	FeatureUUID              uint16 = 0x2ACC
+	RowerDataUUID            uint16 = 0x2AD1
+	ControlPointUUID         uint16 = 0x2AD9
+	FitnessMachineStatusUUID uint16 = 0x2ADA
+)
+
+// Fitness Machine Features field of the Feature characteristic.
+const (
+	FeatureAverageSpeed        uint32 = 1 << 0
+	FeatureCadence             uint32 = 1 << 1
+	FeatureTotalDistance       uint32 = 1 << 2
+	FeatureInclination         uint32 = 1 << 3
+	FeatureElevationGain       uint32 = 1 << 4
+	FeaturePace                uint32 = 1 << 5
+	FeatureStepCount           uint32 = 1 << 6
+	FeatureResistanceLevel     uint32 = 1 << 7
+	FeatureStrideCount         uint32 = 1 << 8
+	FeatureExpendedEnergy      uint32 = 1 << 9
+	FeatureHeartRate           uint32 = 1 << 10
+	FeatureMetabolicEquivalent uint32 = 1 << 11
+	FeatureElapsedTime         uint32 = 1 << 12
+	FeatureRemainingTime       uint32 = 1 << 13
+	FeaturePowerMeasurement    uint32 = 1 << 14
+)
+
+// Target Setting Features field of the Feature characteristic.
+const (
+	TargetSpeed       uint32 = 1 << 0
+	TargetInclination uint32 = 1 << 1
+	TargetResistance  uint32 = 1 << 2
+	TargetPower       uint32 = 1 << 3
+	TargetHeartRate   uint32 = 1 << 4
+)
+
+// Features is the value of the Feature characteristic.
+type Features struct {
+	Machine        uint32 // Feature constants
+	TargetSettings uint32 // Target constants
+}
+
+// RowerFeatures are the features of a Concept2 rower as reported by a Bridge.
+var RowerFeatures = Features{
+	Machine: FeatureCadence | FeatureTotalDistance | FeaturePace | FeatureExpendedEnergy | FeatureHeartRate |
+		FeatureElapsedTime | FeaturePowerMeasurement,
+	TargetSettings: TargetPower,
+}
+
+// Encode returns the payload of the Feature characteristic.
+func (f Features) Encode() []byte {
+	b := binary.LittleEndian.AppendUint32(nil, f.Machine)
+	return binary.LittleEndian.AppendUint32(b, f.TargetSettings)
+}
+
+// Op codes of the Fitness Machine Status characteristic.
+const (
+	StatusReset                 byte = 0x01
+	StatusStoppedOrPaused       byte = 0x02 // Followed by StopParam or PauseParam
+	StatusStoppedBySafetyKey    byte = 0x03
+	StatusStartedOrResumed      byte = 0x04
+	StatusTargetPowerChanged    byte = 0x08 // Followed by the new target power, sint16 in W
+	StatusControlPermissionLost byte = 0xFF
+)
+
+// Op codes of the Control Point characteristic.
+const (
+	OpRequestControl byte = 0x00
+	OpReset          byte = 0x01
+	OpSetTargetPower byte = 0x05 // Followed by the target power, sint16 in W
+	OpStartOrResume  byte = 0x07
+	OpStopOrPause    byte = 0x08 // Followed by StopParam or PauseParam
+	OpResponseCode   byte = 0x80 // Followed by the op code of the request and a Result constant
+)
+
+// Parameters of OpStopOrPause and StatusStoppedOrPaused.
+const (
+	StopParam  byte = 0x01
+	PauseParam byte = 0x02
+)
+
+// Result codes of Control Point responses.
+const (
+	ResultSuccess             byte = 0x01
+	ResultOpCodeNotSupported  byte = 0x02
+	ResultInvalidParameter    byte = 0x03
+	ResultOperationFailed     byte = 0x04
+	ResultControlNotPermitted byte = 0x05
+)
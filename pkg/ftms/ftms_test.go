@@ -0,0 +1,13 @@
+package ftms
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestFeaturesEncode(t *testing.T) {
+	want := []byte{0x26, 0x56, 0x00, 0x00, 0x08, 0x00, 0x00, 0x00}
+	if got := RowerFeatures.Encode(); !bytes.Equal(got, want) {
+		t.Errorf("payload mismatch:\ngot:  % X\nwant: % X", got, want)
+	}
+}
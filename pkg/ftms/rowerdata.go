@@ -0,0 +1,189 @@
+package ftms
+
+import (
+	"encoding/binary"
+	"math"
+	"time"
+)
+
+// RowerDataFlags is the Flags field of the Rower Data characteristic. Every flag but MoreData marks an optional field
+// as present.
+type RowerDataFlags uint16
+
+const (
+	// MoreData is set when the stroke rate and stroke count are absent, as in every notification but the last of a
+	// record split by RowerData.Split.
+	MoreData RowerDataFlags = 1 << iota
+	AverageStrokeRatePresent
+	TotalDistancePresent
+	InstantaneousPacePresent
+	AveragePacePresent
+	InstantaneousPowerPresent
+	AveragePowerPresent
+	ResistanceLevelPresent
+	ExpendedEnergyPresent
+	HeartRatePresent
+	MetabolicEquivalentPresent
+	ElapsedTimePresent
+	RemainingTimePresent
+)
+
+// RowerData is a record of the Rower Data characteristic. Only the fields selected by Flags are encoded; values
+// outside the range of their field are clamped.
+type RowerData struct {
+	Flags RowerDataFlags
+
+	StrokeRate          float64 // Strokes per minute, with 0.5 resolution
+	StrokeCount         int
+	AverageStrokeRate   float64       // Strokes per minute, with 0.5 resolution
+	TotalDistance       int           // Meters
+	Pace                time.Duration // Instantaneous time per 500 m, with 1 s resolution
+	AveragePace         time.Duration // Time per 500 m, with 1 s resolution
+	Power               int           // Instantaneous watts
+	AveragePower        int           // Watts
+	ResistanceLevel     int
+	TotalEnergy         int           // kcal
+	EnergyPerHour       int           // kcal
+	EnergyPerMinute     int           // kcal
+	HeartRate           int           // Beats per minute
+	MetabolicEquivalent float64       // With 0.1 resolution
+	ElapsedTime         time.Duration // With 1 s resolution
+	RemainingTime       time.Duration // With 1 s resolution
+}
+
+// field is the encoding of one field of a record, with the flag that marks it present.
+type field struct {
+	flag RowerDataFlags
+	data []byte
+}
+
+// Encode returns the record as a single payload. Records with many fields can exceed the 20 bytes a notification
+// carries at the default ATT MTU; use Split for those.
+func (r RowerData) Encode() []byte {
+	return encodeFields(r.Flags, r.fields())
+}
+
+// Split returns the record as payloads of at most maxSize bytes, as the FTMS specification allows for records that
+// do not fit a notification. Every payload but the last has MoreData set, and the stroke rate and count go in the
+// last. A maxSize too small for any field yields payloads with one field each.
+func (r RowerData) Split(maxSize int) [][]byte {
+	fields := r.fields()
+	var head field
+	if r.Flags&MoreData == 0 {
+		head, fields = fields[0], fields[1:]
+	}
+
+	size := func(fields []field) int {
+		n := 2
+		for _, f := range fields {
+			n += len(f.data)
+		}
+		return n
+	}
+
+	// Without the stroke fields, the last payload holds at least one field.
+	var payloads [][]byte
+	for len(fields) > 0 && size(fields)+len(head.data) > maxSize && (head.data != nil || len(fields) > 1) {
+		n := 1
+		for n < len(fields) && size(fields[:n+1]) <= maxSize {
+			n++
+		}
+		payloads = append(payloads, encodeFields(MoreData, fields[:n]))
+		fields = fields[n:]
+	}
+
+	flags := MoreData
+	if head.data != nil {
+		flags = 0
+		fields = append([]field{head}, fields...)
+	}
+	return append(payloads, encodeFields(flags, fields))
+}
+
+// encodeFields returns a payload with the given fields, flagging them as present alongside flags.
+func encodeFields(flags RowerDataFlags, fields []field) []byte {
+	for _, f := range fields {
+		flags |= f.flag
+	}
+	b := binary.LittleEndian.AppendUint16(nil, uint16(flags))
+	for _, f := range fields {
+		b = append(b, f.data...)
+	}
+	return b
+}
+
+// fields returns the encoding of the present fields in the order of the specification. The stroke rate and count,
+// present when MoreData is clear, share a field with a zero flag.
+func (r RowerData) fields() []field {
+	var fields []field
+	add := func(flag RowerDataFlags, data []byte) {
+		fields = append(fields, field{flag, data})
+	}
+
+	if r.Flags&MoreData == 0 {
+		add(0, binary.LittleEndian.AppendUint16([]byte{halves(r.StrokeRate)}, clampUint16(r.StrokeCount)))
+	}
+	if r.Flags&AverageStrokeRatePresent != 0 {
+		add(AverageStrokeRatePresent, []byte{halves(r.AverageStrokeRate)})
+	}
+	if r.Flags&TotalDistancePresent != 0 {
+		d := uint32(min(max(r.TotalDistance, 0), 1<<24-1))
+		add(TotalDistancePresent, []byte{byte(d), byte(d >> 8), byte(d >> 16)})
+	}
+	if r.Flags&InstantaneousPacePresent != 0 {
+		add(InstantaneousPacePresent, binary.LittleEndian.AppendUint16(nil, seconds(r.Pace)))
+	}
+	if r.Flags&AveragePacePresent != 0 {
+		add(AveragePacePresent, binary.LittleEndian.AppendUint16(nil, seconds(r.AveragePace)))
+	}
+	if r.Flags&InstantaneousPowerPresent != 0 {
+		add(InstantaneousPowerPresent, binary.LittleEndian.AppendUint16(nil, clampInt16(r.Power)))
+	}
+	if r.Flags&AveragePowerPresent != 0 {
+		add(AveragePowerPresent, binary.LittleEndian.AppendUint16(nil, clampInt16(r.AveragePower)))
+	}
+	if r.Flags&ResistanceLevelPresent != 0 {
+		add(ResistanceLevelPresent, binary.LittleEndian.AppendUint16(nil, clampInt16(r.ResistanceLevel)))
+	}
+	if r.Flags&ExpendedEnergyPresent != 0 {
+		b := binary.LittleEndian.AppendUint16(nil, clampUint16(r.TotalEnergy))
+		b = binary.LittleEndian.AppendUint16(b, clampUint16(r.EnergyPerHour))
+		add(ExpendedEnergyPresent, append(b, clampUint8(r.EnergyPerMinute)))
+	}
+	if r.Flags&HeartRatePresent != 0 {
+		add(HeartRatePresent, []byte{clampUint8(r.HeartRate)})
+	}
+	if r.Flags&MetabolicEquivalentPresent != 0 {
+		add(MetabolicEquivalentPresent, []byte{clampUint8(int(math.Round(r.MetabolicEquivalent * 10)))})
+	}
+	if r.Flags&ElapsedTimePresent != 0 {
+		add(ElapsedTimePresent, binary.LittleEndian.AppendUint16(nil, seconds(r.ElapsedTime)))
+	}
+	if r.Flags&RemainingTimePresent != 0 {
+		add(RemainingTimePresent, binary.LittleEndian.AppendUint16(nil, seconds(r.RemainingTime)))
+	}
+
+	return fields
+}
+
+// halves returns a rate with 0.5 resolution.
+func halves(rate float64) byte {
+	return clampUint8(int(math.Round(rate * 2)))
+}
+
+// seconds returns a duration in whole seconds.
+func seconds(d time.Duration) uint16 {
+	return clampUint16(int(d.Round(time.Second) / time.Second))
+}
+
+func clampUint8(v int) byte {
+	return byte(min(max(v, 0), math.MaxUint8))
+}
+
+func clampUint16(v int) uint16 {
+	return uint16(min(max(v, 0), math.MaxUint16))
+}
+
+func clampInt16(v int) uint16 {
+	return uint16(int16(min(max(v, math.MinInt16), math.MaxInt16)))
+}
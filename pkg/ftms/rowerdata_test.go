@@ -0,0 +1,128 @@
+package ftms
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+	"time"
+)
+
+// fullRecord has every field of the Rower Data characteristic present.
+var fullRecord = RowerData{
+	Flags: AverageStrokeRatePresent | TotalDistancePresent | InstantaneousPacePresent | AveragePacePresent |
+		InstantaneousPowerPresent | AveragePowerPresent | ResistanceLevelPresent | ExpendedEnergyPresent |
+		HeartRatePresent | MetabolicEquivalentPresent | ElapsedTimePresent | RemainingTimePresent,
+	StrokeRate:          24.5,
+	StrokeCount:         300,
+	AverageStrokeRate:   24,
+	TotalDistance:       70000,
+	Pace:                119600 * time.Millisecond,
+	AveragePace:         121 * time.Second,
+	Power:               203,
+	AveragePower:        -5,
+	ResistanceLevel:     12,
+	TotalEnergy:         350,
+	EnergyPerHour:       998,
+	EnergyPerMinute:     17,
+	HeartRate:           152,
+	MetabolicEquivalent: 9.3,
+	ElapsedTime:         20*time.Minute + 35400*time.Millisecond,
+	RemainingTime:       9*time.Minute + 25*time.Second,
+}
+
+func TestRowerDataEncode(t *testing.T) {
+	tests := []struct {
+		name string
+		data RowerData
+		want []byte
+	}{
+		{
+			name: "stroke rate and count",
+			data: RowerData{StrokeRate: 24.5, StrokeCount: 300},
+			want: []byte{0x00, 0x00, 0x31, 0x2C, 0x01},
+		},
+		{
+			name: "all fields",
+			data: fullRecord,
+			want: []byte{
+				0xFE, 0x1F,
+				0x31, 0x2C, 0x01, // 24.5 spm, 300 strokes
+				0x30,             // 24 spm
+				0x70, 0x11, 0x01, // 70000 m
+				0x78, 0x00, // 120 s
+				0x79, 0x00, // 121 s
+				0xCB, 0x00, // 203 W
+				0xFB, 0xFF, // -5 W
+				0x0C, 0x00,
+				0x5E, 0x01, 0xE6, 0x03, 0x11, // 350 kcal, 998 kcal/h, 17 kcal/min
+				0x98,       // 152 bpm
+				0x5D,       // 9.3 MET
+				0xD3, 0x04, // 1235 s
+				0x35, 0x02, // 565 s
+			},
+		},
+		{
+			name: "clamped",
+			data: RowerData{
+				Flags:         MoreData | TotalDistancePresent | InstantaneousPowerPresent,
+				TotalDistance: 1 << 25,
+				Power:         40000,
+			},
+			want: []byte{0x25, 0x00, 0xFF, 0xFF, 0xFF, 0xFF, 0x7F},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.data.Encode(); !bytes.Equal(got, tt.want) {
+				t.Errorf("payload mismatch:\ngot:  % X\nwant: % X", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRowerDataSplit(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    RowerData
+		maxSize int
+		want    [][]byte
+	}{
+		{
+			name:    "fits",
+			data:    fullRecord,
+			maxSize: 64,
+			want:    [][]byte{fullRecord.Encode()},
+		},
+		{
+			name:    "default MTU",
+			data:    fullRecord,
+			maxSize: 20,
+			want: [][]byte{
+				{0xFF, 0x00, 0x30, 0x70, 0x11, 0x01, 0x78, 0x00, 0x79, 0x00, 0xCB, 0x00, 0xFB, 0xFF, 0x0C, 0x00},
+				{0x00, 0x1F, 0x31, 0x2C, 0x01, 0x5E, 0x01, 0xE6, 0x03, 0x11, 0x98, 0x5D, 0xD3, 0x04, 0x35, 0x02},
+			},
+		},
+		{
+			name: "one field per payload",
+			data: RowerData{
+				Flags:         MoreData | TotalDistancePresent | InstantaneousPowerPresent,
+				TotalDistance: 1 << 25,
+				Power:         40000,
+			},
+			maxSize: 1,
+			want: [][]byte{
+				{0x05, 0x00, 0xFF, 0xFF, 0xFF},
+				{0x21, 0x00, 0xFF, 0x7F},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.data.Split(tt.maxSize); !slices.EqualFunc(got, tt.want, bytes.Equal) {
+				t.Errorf("payloads mismatch:\ngot:  % X\nwant: % X", got, tt.want)
+			}
+		})
+	}
+}
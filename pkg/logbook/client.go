@@ -0,0 +1,124 @@
+package logbook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// DefaultBaseURL is the base URL of the production Logbook. The development server is at
+// https://log-dev.concept2.com.
+const DefaultBaseURL = "https://log.concept2.com"
+
+// mediaType is the version of the API the client speaks.
+const mediaType = "application/vnd.c2logbook.v1+json"
+
+// Option configures a Client created by NewClient.
+type Option func(*Client)
+
+// WithBaseURL sends requests to baseURL instead of DefaultBaseURL, e.g. the development server or an httptest.Server.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithHTTPClient sends requests with hc instead of http.DefaultClient.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.http = hc
+	}
+}
+
+// Client calls the Logbook API on behalf of a user.
+type Client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+// NewClient returns a client authenticating with an OAuth access token. Obtaining and refreshing the token is left to
+// the caller, e.g. with golang.org/x/oauth2.
+func NewClient(token string, opts ...Option) *Client {
+	c := &Client{baseURL: DefaultBaseURL, token: token, http: http.DefaultClient}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// APIError is an error response of the Logbook API.
+type APIError struct {
+	StatusCode int                 `json:"status_code"`
+	Message    string              `json:"message"`
+	Errors     map[string][]string `json:"errors"` // Validation errors by field
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("logbook: %d %s", e.StatusCode, e.Message)
+	for _, field := range slices.Sorted(maps.Keys(e.Errors)) {
+		msg += fmt.Sprintf("; %s: %s", field, strings.Join(e.Errors[field], ", "))
+	}
+	return msg
+}
+
+// AddResult adds r to the logbook of the user, "me" for the owner of the token, and returns the result as stored,
+// with its ID. Error responses are returned as an *APIError.
+func (c *Client) AddResult(ctx context.Context, user string, r Result) (Result, error) {
+	body, err := json.Marshal(r)
+	if err != nil {
+		return Result{}, fmt.Errorf("logbook: encoding result: %w", err)
+	}
+
+	var resp struct {
+		Data Result `json:"data"`
+	}
+	path := "/api/users/" + url.PathEscape(user) + "/results"
+	if err := c.do(ctx, http.MethodPost, path, body, &resp); err != nil {
+		return Result{}, err
+	}
+	return resp.Data, nil
+}
+
+// do sends a request and decodes the JSON response into out.
+func (c *Client) do(ctx context.Context, method, path string, body []byte, out any) error {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("logbook: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", mediaType)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("logbook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("logbook: reading response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := &APIError{}
+		if json.Unmarshal(b, apiErr) != nil || apiErr.Message == "" {
+			apiErr.Message = http.StatusText(resp.StatusCode)
+		}
+		apiErr.StatusCode = resp.StatusCode
+		return apiErr
+	}
+
+	if err := json.Unmarshal(b, out); err != nil {
+		return fmt.Errorf("logbook: decoding response: %w", err)
+	}
+	return nil
+}
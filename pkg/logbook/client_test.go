@@ -0,0 +1,79 @@
+package logbook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClientAddResult(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var posted Result
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/users/me/results" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("authorization: got %q", got)
+		}
+		if got := r.Header.Get("Accept"); got != "application/vnd.c2logbook.v1+json" {
+			t.Errorf("accept: got %q", got)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&posted); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+
+		if posted.Distance == 0 {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"status_code":422,"message":"The data could not be validated",` +
+				`"errors":{"time":["The time field is required."],"distance":["The distance must be at least 100."]}}`))
+			return
+		}
+
+		stored := posted
+		stored.ID = 42
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]any{"data": stored})
+	}))
+	defer srv.Close()
+
+	c := NewClient("secret", WithBaseURL(srv.URL+"/"), WithHTTPClient(srv.Client()))
+
+	r := Result{
+		Type:        TypeRower,
+		Date:        Date(time.Date(2024, 3, 9, 7, 30, 0, 0, time.UTC)),
+		Distance:    2000,
+		Time:        Duration(7 * time.Minute),
+		WeightClass: WeightClassLight,
+	}
+	got, err := c.AddResult(ctx, "me", r)
+	if err != nil {
+		t.Fatalf("AddResult failed: %v", err)
+	}
+	if !reflect.DeepEqual(posted, r) {
+		t.Errorf("posted result mismatch:\ngot:  %+v\nwant: %+v", posted, r)
+	}
+	want := r
+	want.ID = 42
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("stored result mismatch:\ngot:  %+v\nwant: %+v", got, want)
+	}
+
+	_, err = c.AddResult(ctx, "me", Result{Type: TypeRower})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("got %v, want an *APIError with status 422", err)
+	}
+	want422 := "logbook: 422 The data could not be validated; distance: The distance must be at least 100.; " +
+		"time: The time field is required."
+	if apiErr.Error() != want422 {
+		t.Errorf("error message: got %q, want %q", apiErr.Error(), want422)
+	}
+}
@@ -0,0 +1,141 @@
+package logbook
+
+import (
+	"math"
+	"time"
+
+	"github.com/seagrayinc/gorow/pkg/pm5"
+)
+
+// NewResult returns the result of a rowing workout that started at start, from its summary and the strokes a
+// pm5.StrokeDetector recorded during it, which may be nil. The summary is the one returned by a pm5.IntervalRecorder,
+// or the end of workout summary of package ble. The former reports a single-piece workout as one interval, so only
+// the latter fills Workout.Splits. The weight class defaults to heavy; set it, the calories and the drag factor on
+// the result as needed.
+//
+// The Timezone is the name of the location of start, unless that is time.Local, whose name is unknown. The PM does
+// not report whether the intervals of a variable interval workout were time or distance intervals, so they are
+// exported as distance intervals; correct Workout.Intervals[i].Type if needed.
+func NewResult(start time.Time, summary pm5.WorkoutSummary, strokes []pm5.Stroke) Result {
+	r := Result{
+		Type:        TypeRower,
+		Date:        Date(start),
+		WeightClass: WeightClassHeavy,
+		WorkoutType: workoutType(summary.WorkoutType),
+	}
+	if loc := start.Location(); loc != time.Local {
+		r.Timezone = loc.String()
+	}
+
+	var work, strokeRate, heartRate, hrTime time.Duration
+	for _, in := range summary.Intervals {
+		work += in.Time
+		r.Distance += in.Meters
+		strokeRate += in.Time * time.Duration(in.StrokeRate)
+		if in.HeartRate > 0 {
+			heartRate += in.Time * time.Duration(in.HeartRate)
+			hrTime += in.Time
+		}
+	}
+	r.Time = Duration(work)
+	if work > 0 {
+		r.StrokeRate = int(math.Round(float64(strokeRate) / float64(work)))
+	}
+	if hrTime > 0 {
+		r.HeartRate = &HeartRate{Average: int(math.Round(float64(heartRate) / float64(hrTime)))}
+	}
+
+	if t, ok := intervalType(summary.WorkoutType); ok {
+		r.Workout = &Workout{}
+		for _, in := range summary.Intervals {
+			r.Workout.Intervals = append(r.Workout.Intervals, Interval{
+				Type:       t,
+				Time:       Duration(in.Time),
+				Distance:   in.Meters,
+				RestTime:   Duration(in.Rest),
+				StrokeRate: in.StrokeRate,
+				HeartRate:  heartRateOf(in),
+			})
+		}
+	} else if len(summary.Intervals) > 1 {
+		r.Workout = &Workout{}
+		for _, in := range summary.Intervals {
+			r.Workout.Splits = append(r.Workout.Splits, Split{
+				Time:       Duration(in.Time),
+				Distance:   in.Meters,
+				StrokeRate: in.StrokeRate,
+				HeartRate:  heartRateOf(in),
+			})
+		}
+	}
+
+	var meters float64
+	for _, s := range strokes {
+		r.StrokeCount += 1 + s.Missed
+		meters += s.Distance
+
+		sample := Stroke{
+			Time:       Duration(max(s.Time.Sub(start), 0)),
+			Decimeters: int(math.Round(meters * 10)),
+		}
+		if d := s.DriveTime + s.RecoveryTime; d > 0 {
+			sample.StrokeRate = int(math.Round(time.Minute.Seconds() / d.Seconds()))
+			if s.Distance > 0 {
+				sample.Pace = Duration(time.Duration(float64(d) * 500 / s.Distance).Round(100 * time.Millisecond))
+			}
+		}
+		r.StrokeData = append(r.StrokeData, sample)
+	}
+
+	return r
+}
+
+// workoutType returns the Logbook workout type of a PM workout type.
+func workoutType(t pm5.WorkoutType) string {
+	switch t {
+	case pm5.WorkoutTypeJustRowNoSplits, pm5.WorkoutTypeJustRowSplits:
+		return WorkoutTypeJustRow
+	case pm5.WorkoutTypeFixedDistanceNoSplits, pm5.WorkoutTypeFixedDistanceSplits:
+		return WorkoutTypeFixedDistanceSplits
+	case pm5.WorkoutTypeFixedTimeNoSplits, pm5.WorkoutTypeFixedTimeSplits:
+		return WorkoutTypeFixedTimeSplits
+	case pm5.WorkoutTypeFixedTimeInterval:
+		return WorkoutTypeFixedTimeInterval
+	case pm5.WorkoutTypeFixedDistanceInterval:
+		return WorkoutTypeFixedDistanceInterval
+	case pm5.WorkoutTypeVariableInterval:
+		return WorkoutTypeVariableInterval
+	case pm5.WorkoutTypeVariableUndefinedRestInterval:
+		return WorkoutTypeVariableIntervalUndefinedRest
+	case pm5.WorkoutTypeFixedCalorieSplits:
+		return WorkoutTypeFixedCalorie
+	case pm5.WorkoutTypeFixedWattMinuteSplits:
+		return WorkoutTypeFixedWattMinute
+	case pm5.WorkoutTypeFixedCalorieInterval:
+		return WorkoutTypeFixedCalorieInterval
+	default:
+		return WorkoutTypeUnknown
+	}
+}
+
+// intervalType returns the Logbook type of the intervals of a PM workout type, and false for single pieces.
+func intervalType(t pm5.WorkoutType) (string, bool) {
+	switch t {
+	case pm5.WorkoutTypeFixedTimeInterval:
+		return IntervalTypeTime, true
+	case pm5.WorkoutTypeFixedDistanceInterval, pm5.WorkoutTypeVariableInterval,
+		pm5.WorkoutTypeVariableUndefinedRestInterval:
+		return IntervalTypeDistance, true
+	case pm5.WorkoutTypeFixedCalorieInterval:
+		return IntervalTypeCalorie, true
+	default:
+		return "", false
+	}
+}
+
+func heartRateOf(in pm5.IntervalSummary) *HeartRate {
+	if in.HeartRate == 0 {
+		return nil
+	}
+	return &HeartRate{Average: in.HeartRate}
+}
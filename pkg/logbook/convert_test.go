@@ -0,0 +1,171 @@
+package logbook
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/seagrayinc/gorow/pkg/pm5"
+)
+
+func TestNewResult(t *testing.T) {
+	start := time.Date(2024, 3, 9, 7, 30, 0, 0, time.FixedZone("Europe/London", 0))
+
+	tests := []struct {
+		name    string
+		summary pm5.WorkoutSummary
+		strokes []pm5.Stroke
+		want    Result
+	}{
+		{
+			name: "intervals",
+			summary: pm5.WorkoutSummary{
+				WorkoutType: pm5.WorkoutTypeFixedDistanceInterval,
+				Intervals: []pm5.IntervalSummary{
+					{Time: 4 * time.Minute, Meters: 1000, StrokeRate: 26, HeartRate: 160, Rest: 2 * time.Minute},
+					{Time: 236 * time.Second, Meters: 1000, StrokeRate: 28},
+				},
+			},
+			want: Result{
+				Type:        TypeRower,
+				Date:        Date(start),
+				Timezone:    "Europe/London",
+				Distance:    2000,
+				Time:        Duration(476 * time.Second),
+				WeightClass: WeightClassHeavy,
+				WorkoutType: WorkoutTypeFixedDistanceInterval,
+				StrokeRate:  27,
+				HeartRate:   &HeartRate{Average: 160},
+				Workout: &Workout{Intervals: []Interval{
+					{
+						Type:       IntervalTypeDistance,
+						Time:       Duration(4 * time.Minute),
+						Distance:   1000,
+						RestTime:   Duration(2 * time.Minute),
+						StrokeRate: 26,
+						HeartRate:  &HeartRate{Average: 160},
+					},
+					{Type: IntervalTypeDistance, Time: Duration(236 * time.Second), Distance: 1000, StrokeRate: 28},
+				}},
+			},
+		},
+		{
+			name: "splits and strokes",
+			summary: pm5.WorkoutSummary{
+				WorkoutType: pm5.WorkoutTypeFixedTimeSplits,
+				Intervals: []pm5.IntervalSummary{
+					{Time: time.Minute, Meters: 250, StrokeRate: 24},
+					{Time: time.Minute, Meters: 245, StrokeRate: 24},
+				},
+			},
+			strokes: []pm5.Stroke{
+				{Time: start.Add(2500 * time.Millisecond), DriveTime: 800 * time.Millisecond, RecoveryTime: 1700 * time.Millisecond, Distance: 10.4},
+				{Time: start.Add(7500 * time.Millisecond), Missed: 1, DriveTime: 800 * time.Millisecond, RecoveryTime: 1700 * time.Millisecond, Distance: 10.6},
+			},
+			want: Result{
+				Type:        TypeRower,
+				Date:        Date(start),
+				Timezone:    "Europe/London",
+				Distance:    495,
+				Time:        Duration(2 * time.Minute),
+				WeightClass: WeightClassHeavy,
+				WorkoutType: WorkoutTypeFixedTimeSplits,
+				StrokeRate:  24,
+				StrokeCount: 3,
+				Workout: &Workout{Splits: []Split{
+					{Time: Duration(time.Minute), Distance: 250, StrokeRate: 24},
+					{Time: Duration(time.Minute), Distance: 245, StrokeRate: 24},
+				}},
+				StrokeData: []Stroke{
+					{Time: Duration(2500 * time.Millisecond), Decimeters: 104, Pace: Duration(120200 * time.Millisecond), StrokeRate: 24},
+					{Time: Duration(7500 * time.Millisecond), Decimeters: 210, Pace: Duration(117900 * time.Millisecond), StrokeRate: 24},
+				},
+			},
+		},
+		{
+			name: "single piece",
+			summary: pm5.WorkoutSummary{
+				WorkoutType: pm5.WorkoutTypeFixedDistanceNoSplits,
+				Intervals:   []pm5.IntervalSummary{{Time: 2 * time.Minute, Meters: 500, StrokeRate: 30}},
+			},
+			want: Result{
+				Type:        TypeRower,
+				Date:        Date(start),
+				Timezone:    "Europe/London",
+				Distance:    500,
+				Time:        Duration(2 * time.Minute),
+				WeightClass: WeightClassHeavy,
+				WorkoutType: WorkoutTypeFixedDistanceSplits,
+				StrokeRate:  30,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewResult(start, tt.summary, tt.strokes)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("result mismatch:\ngot:  %+v\nwant: %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewResultFromEmulator(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	e := pm5.NewEmulator(pm5.EmulatorConfig{Manual: true, HeartRate: 150})
+	p, err := pm5.Open(ctx, pm5.WithDevice(e))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	workout := pm5.Workout{Intervals: []pm5.Interval{
+		{Duration: pm5.Meters(500), Rest: time.Minute},
+		{Duration: pm5.Meters(250), Rest: 30 * time.Second},
+		{Duration: pm5.Meters(500)},
+	}}
+	if err := pm5.ProgramWorkout(ctx, p, workout); err != nil {
+		t.Fatalf("ProgramWorkout failed: %v", err)
+	}
+
+	// Every interval is recorded as the session sees it end.
+	session := pm5.NewSession()
+	recorder := pm5.NewIntervalRecorder(p)
+	for range 40 {
+		e.Advance(15 * time.Second)
+		state, err := pm5.Query[pm5.GetWorkoutStateResponse](ctx, p, pm5.GetWorkoutState())
+		if err != nil {
+			t.Fatalf("Query failed: %v", err)
+		}
+		for _, ev := range session.Observe(time.Now(), state) {
+			if err := recorder.Observe(ctx, ev); err != nil {
+				t.Fatalf("Observe failed: %v", err)
+			}
+		}
+	}
+
+	summary, err := recorder.Summary(ctx)
+	if err != nil {
+		t.Fatalf("Summary failed: %v", err)
+	}
+
+	got := NewResult(time.Now(), summary, nil)
+	if got.WorkoutType != WorkoutTypeVariableInterval || got.Distance != 1250 || got.StrokeRate != 24 {
+		t.Errorf("result mismatch: %+v", got)
+	}
+	if got.HeartRate == nil || got.HeartRate.Average != 150 {
+		t.Errorf("heart rate: got %+v, want 150", got.HeartRate)
+	}
+	if got.Workout == nil || len(got.Workout.Intervals) != len(workout.Intervals) {
+		t.Fatalf("intervals mismatch: %+v", got.Workout)
+	}
+	for i, in := range got.Workout.Intervals {
+		want := workout.Intervals[i]
+		if in.Type != IntervalTypeDistance || in.Distance != int(want.Duration.Value) || in.RestTime != Duration(want.Rest) {
+			t.Errorf("interval %d mismatch: %+v", i+1, in)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+// Package logbook exports workouts to the Concept2 Online Logbook. Result follows the result schema of the Logbook
+// API, documented at https://log.concept2.com/developers/documentation/, and Client adds results to a user's logbook.
+package logbook
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Result is a workout result of the Logbook API. Fields the Logbook sets, such as ID, are left out of requests when
+// zero.
+type Result struct {
+	ID            int        `json:"id,omitempty"`
+	Type          string     `json:"type"` // TypeRower
+	Date          Date       `json:"date"`
+	Timezone      string     `json:"timezone,omitempty"` // IANA name of the zone of Date
+	Distance      int        `json:"distance"`           // Meters, excluding rest
+	Time          Duration   `json:"time"`               // Excluding rest
+	WeightClass   string     `json:"weight_class"`       // WeightClassHeavy or WeightClassLight
+	WorkoutType   string     `json:"workout_type,omitempty"`
+	StrokeRate    int        `json:"stroke_rate,omitempty"` // Average strokes per minute
+	StrokeCount   int        `json:"stroke_count,omitempty"`
+	HeartRate     *HeartRate `json:"heart_rate,omitempty"`
+	CaloriesTotal int        `json:"calories_total,omitempty"`
+	DragFactor    int        `json:"drag_factor,omitempty"`
+	Comments      string     `json:"comments,omitempty"`
+	Workout       *Workout   `json:"workout,omitempty"`
+	StrokeData    []Stroke   `json:"stroke_data,omitempty"`
+}
+
+// Machine types of a Result.
+const (
+	TypeRower  = "rower"
+	TypeSkiErg = "skierg"
+	TypeBike   = "bike"
+)
+
+// Weight classes of a Result.
+const (
+	WeightClassHeavy = "H"
+	WeightClassLight = "L"
+)
+
+// Workout types of a Result.
+const (
+	WorkoutTypeUnknown                       = "unknown"
+	WorkoutTypeJustRow                       = "JustRow"
+	WorkoutTypeFixedDistanceSplits           = "FixedDistanceSplits"
+	WorkoutTypeFixedTimeSplits               = "FixedTimeSplits"
+	WorkoutTypeFixedCalorie                  = "FixedCalorie"
+	WorkoutTypeFixedWattMinute               = "FixedWattMinute"
+	WorkoutTypeFixedTimeInterval             = "FixedTimeInterval"
+	WorkoutTypeFixedDistanceInterval         = "FixedDistanceInterval"
+	WorkoutTypeFixedCalorieInterval          = "FixedCalorieInterval"
+	WorkoutTypeVariableInterval              = "VariableInterval"
+	WorkoutTypeVariableIntervalUndefinedRest = "VariableIntervalUndefinedRest"
+)
+
+// HeartRate summarizes the heart rate of a result, split or interval in beats per minute.
+type HeartRate struct {
+	Average  int `json:"average,omitempty"`
+	Min      int `json:"min,omitempty"`
+	Max      int `json:"max,omitempty"`
+	Ending   int `json:"ending,omitempty"`
+	Recovery int `json:"recovery,omitempty"`
+}
+
+// Workout holds the splits of a single piece, or the intervals of an interval workout.
+type Workout struct {
+	Splits    []Split    `json:"splits,omitempty"`
+	Intervals []Interval `json:"intervals,omitempty"`
+}
+
+// Split is one split of a single piece.
+type Split struct {
+	Time          Duration   `json:"time"`
+	Distance      int        `json:"distance"`
+	StrokeRate    int        `json:"stroke_rate,omitempty"`
+	CaloriesTotal int        `json:"calories_total,omitempty"`
+	HeartRate     *HeartRate `json:"heart_rate,omitempty"`
+}
+
+// Interval is one interval of an interval workout.
+type Interval struct {
+	Type          string     `json:"type"` // IntervalTypeTime, IntervalTypeDistance or IntervalTypeCalorie
+	Time          Duration   `json:"time"`
+	Distance      int        `json:"distance"`
+	RestTime      Duration   `json:"rest_time,omitempty"`
+	RestDistance  int        `json:"rest_distance,omitempty"`
+	StrokeRate    int        `json:"stroke_rate,omitempty"`
+	CaloriesTotal int        `json:"calories_total,omitempty"`
+	HeartRate     *HeartRate `json:"heart_rate,omitempty"`
+}
+
+// Interval types.
+const (
+	IntervalTypeTime     = "time"
+	IntervalTypeDistance = "distance"
+	IntervalTypeCalorie  = "calorie"
+)
+
+// Stroke is one sample of the stroke data of a result.
+type Stroke struct {
+	Time       Duration `json:"t"`   // Since the start of the workout
+	Decimeters int      `json:"d"`   // Distance since the start of the workout
+	Pace       Duration `json:"p"`   // Per 500 m
+	StrokeRate int      `json:"spm"` // Strokes per minute
+	HeartRate  int      `json:"hr"`  // Beats per minute, zero when unknown
+}
+
+// Duration is a time.Duration encoded as tenths of a second, the unit of times in the Logbook API.
+type Duration time.Duration
+
+// MarshalJSON encodes d in tenths of a second, rounded.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(int64(math.Round(float64(d) / float64(100*time.Millisecond))))
+}
+
+// UnmarshalJSON decodes a number of tenths of a second.
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var tenths float64
+	if err := json.Unmarshal(b, &tenths); err != nil {
+		return fmt.Errorf("logbook: duration: %w", err)
+	}
+	*d = Duration(math.Round(tenths * float64(100*time.Millisecond)))
+	return nil
+}
+
+// dateLayout is the layout of dates in the Logbook API. Dates carry no zone; it is given by Result.Timezone.
+const dateLayout = "2006-01-02 15:04:05"
+
+// Date is a time.Time encoded in the wall clock time of its location, the format of dates in the Logbook API.
+// Decoded dates are in UTC, whatever their Timezone.
+type Date time.Time
+
+// MarshalJSON encodes the wall clock time of d.
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Time(d).Format(dateLayout))
+}
+
+// UnmarshalJSON decodes a date of the Logbook API.
+func (d *Date) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("logbook: date: %w", err)
+	}
+	t, err := time.Parse(dateLayout, s)
+	if err != nil {
+		return fmt.Errorf("logbook: date: %w", err)
+	}
+	*d = Date(t)
+	return nil
+}
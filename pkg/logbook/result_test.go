@@ -0,0 +1,50 @@
+package logbook
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestResultJSON(t *testing.T) {
+	r := Result{
+		Type:        TypeRower,
+		Date:        Date(time.Date(2024, 3, 9, 7, 30, 0, 0, time.UTC)),
+		Timezone:    "UTC",
+		Distance:    2000,
+		Time:        Duration(7*time.Minute + 12300*time.Millisecond),
+		WeightClass: WeightClassHeavy,
+		WorkoutType: WorkoutTypeFixedDistanceInterval,
+		StrokeRate:  28,
+		HeartRate:   &HeartRate{Average: 165},
+		Workout: &Workout{Intervals: []Interval{
+			{Type: IntervalTypeDistance, Time: Duration(216 * time.Second), Distance: 1000, RestTime: Duration(time.Minute)},
+		}},
+		StrokeData: []Stroke{{Time: Duration(2100 * time.Millisecond), Decimeters: 98, Pace: Duration(106 * time.Second), StrokeRate: 29}},
+	}
+
+	b, err := json.Marshal(r)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	want := `{"type":"rower","date":"2024-03-09 07:30:00","timezone":"UTC","distance":2000,"time":4323,` +
+		`"weight_class":"H","workout_type":"FixedDistanceInterval","stroke_rate":28,"heart_rate":{"average":165},` +
+		`"workout":{"intervals":[{"type":"distance","time":2160,"distance":1000,"rest_time":600}]},` +
+		`"stroke_data":[{"t":21,"d":98,"p":1060,"spm":29,"hr":0}]}`
+	if string(b) != want {
+		t.Errorf("JSON mismatch:\ngot:  %s\nwant: %s", b, want)
+	}
+
+	var got Result
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, r) {
+		t.Errorf("round trip mismatch:\ngot:  %+v\nwant: %+v", got, r)
+	}
+
+	if err := json.Unmarshal([]byte(`{"date":"9 March"}`), &got); err == nil {
+		t.Error("Unmarshal accepted an invalid date")
+	}
+}
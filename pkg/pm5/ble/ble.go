@@ -0,0 +1,209 @@
+// Package ble decodes the Concept2 PM5 Bluetooth LE rowing service defined at
+// https://www.concept2.co.uk/files/pdf/us/monitors/PM5_BluetoothSmartInterfaceDefinition.pdf
+//
+// Notifications are decoded into the same types pkg/pm5 emits for CSAFE responses, so consumers such as
+// pm5.StrokeDetector and pm5.Session work unchanged over BLE. The radio is left to the caller, behind GATTClient.
+package ble
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+)
+
+// Characteristic identifies a PM5 GATT service or characteristic by the 16 bits that vary in its UUID.
+type Characteristic uint16
+
+const (
+	DeviceInformationService Characteristic = 0x0010
+	ControlService           Characteristic = 0x0020
+	RowingService            Characteristic = 0x0030
+
+	ControlReceive Characteristic = 0x0021 // CSAFE frames written to the PM
+	ControlSend    Characteristic = 0x0022 // CSAFE frames notified by the PM
+
+	GeneralStatus                 Characteristic = 0x0031
+	AdditionalStatus1             Characteristic = 0x0032
+	AdditionalStatus2             Characteristic = 0x0033
+	SampleRateControl             Characteristic = 0x0034
+	StrokeData                    Characteristic = 0x0035
+	AdditionalStrokeData          Characteristic = 0x0036
+	SplitIntervalData             Characteristic = 0x0037
+	AdditionalSplitIntervalData   Characteristic = 0x0038
+	EndOfWorkoutSummary           Characteristic = 0x0039
+	EndOfWorkoutAdditionalSummary Characteristic = 0x003A
+	HeartRateBeltInformation      Characteristic = 0x003B
+	ForceCurveData                Characteristic = 0x003D
+	MultiplexedInformation        Characteristic = 0x0080
+)
+
+// UUID returns the 128-bit UUID of the characteristic, e.g. "ce060031-43e5-11e4-916c-0800200c9a66".
+func (c Characteristic) UUID() string {
+	return fmt.Sprintf("ce06%04x-43e5-11e4-916c-0800200c9a66", uint16(c))
+}
+
+func (c Characteristic) String() string {
+	return fmt.Sprintf("0x%04X", uint16(c))
+}
+
+// SampleRate is the rate at which the PM notifies the status and stroke characteristics.
+type SampleRate byte
+
+const (
+	SampleRate1s    SampleRate = 0
+	SampleRate500ms SampleRate = 1
+	SampleRate250ms SampleRate = 2 // The PM's default
+	SampleRate100ms SampleRate = 3
+)
+
+// GATTClient is a connection to a PM5 over Bluetooth LE. Implement it on top of the Bluetooth stack of the platform,
+// or use a MockGATTClient in tests.
+type GATTClient interface {
+	// Subscribe enables notifications of the characteristic with the given UUID and calls notify with the payload of
+	// each one until ctx is done. notify may be called from any goroutine.
+	Subscribe(ctx context.Context, uuid string, notify func(payload []byte)) error
+	// Write writes data to the characteristic with the given UUID.
+	Write(ctx context.Context, uuid string, data []byte) error
+}
+
+// Client decodes the notifications of a PM5 rowing service into events.
+type Client struct {
+	gatt GATTClient
+	log  *slog.Logger
+
+	mu      sync.Mutex
+	decoder *Decoder
+	events  chan any
+	closed  bool
+}
+
+// Option configures a Client opened by Open, or a ControlChannel opened by OpenControlChannel.
+type Option func(*options)
+
+type options struct {
+	multiplexed bool
+	eventBuffer int
+	logger      *slog.Logger
+}
+
+// WithMultiplexed subscribes to the single multiplexed characteristic instead of the individual ones, for platforms
+// that limit the number of notifications a connection can enable.
+func WithMultiplexed() Option {
+	return func(o *options) {
+		o.multiplexed = true
+	}
+}
+
+// WithEventBuffer sets the number of events buffered by Events (default 100). When the buffer is full, the oldest
+// events are dropped.
+func WithEventBuffer(n int) Option {
+	return func(o *options) {
+		o.eventBuffer = n
+	}
+}
+
+// WithLogger sets the logger.
+func WithLogger(l *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
+
+// subscriptions are the characteristics a Client subscribes to without WithMultiplexed.
+var subscriptions = []Characteristic{
+	GeneralStatus,
+	AdditionalStatus1,
+	AdditionalStatus2,
+	StrokeData,
+	AdditionalStrokeData,
+	SplitIntervalData,
+	AdditionalSplitIntervalData,
+	EndOfWorkoutSummary,
+	ForceCurveData,
+}
+
+// Open subscribes to the rowing service of the PM5 connected through gatt. Notifications are decoded and emitted on
+// Events until ctx is done.
+func Open(ctx context.Context, gatt GATTClient, opts ...Option) (*Client, error) {
+	o := options{eventBuffer: 100, logger: slog.Default()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	c := &Client{
+		gatt:    gatt,
+		log:     o.logger,
+		decoder: NewDecoder(),
+		events:  make(chan any, max(o.eventBuffer, 1)),
+	}
+
+	chars := subscriptions
+	if o.multiplexed {
+		chars = []Characteristic{MultiplexedInformation}
+	}
+	for _, ch := range chars {
+		err := gatt.Subscribe(ctx, ch.UUID(), func(payload []byte) {
+			c.notify(ch, payload)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("ble: subscribing to %s: %w", ch, err)
+		}
+	}
+
+	go func() {
+		<-ctx.Done()
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.closed = true
+		close(c.events)
+	}()
+
+	return c, nil
+}
+
+// Events returns a channel that emits every decoded event, and the errors of notifications that could not be decoded.
+// The channel is closed when the context passed to Open is done.
+func (c *Client) Events() <-chan any {
+	return c.events
+}
+
+// SetSampleRate sets the rate at which the PM notifies the status and stroke characteristics.
+func (c *Client) SetSampleRate(ctx context.Context, rate SampleRate) error {
+	if err := c.gatt.Write(ctx, SampleRateControl.UUID(), []byte{byte(rate)}); err != nil {
+		return fmt.Errorf("ble: setting sample rate: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) notify(ch Characteristic, payload []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return
+	}
+
+	events, err := c.decoder.Decode(ch, payload)
+	if err != nil {
+		c.log.Warn("decoding notification failed", slog.Any("error", err))
+		c.publish(err)
+	}
+	for _, e := range events {
+		c.publish(e)
+	}
+}
+
+// publish emits e, dropping the oldest event if the buffer is full. c.mu must be held.
+func (c *Client) publish(e any) {
+	for {
+		select {
+		case c.events <- e:
+			return
+		default:
+		}
+		select {
+		case <-c.events:
+		default:
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package ble
+
+import (
+	"context"
+	"sync"
+)
+
+// MockGATTClient is a GATTClient without a radio. Notifications are injected with Notify.
+type MockGATTClient struct {
+	mu       sync.Mutex
+	handlers map[string]func([]byte)
+
+	// OnWrite, if set, is called with every write to a characteristic.
+	OnWrite func(uuid string, data []byte)
+}
+
+func NewMockGATTClient() *MockGATTClient {
+	return &MockGATTClient{handlers: make(map[string]func([]byte))}
+}
+
+func (m *MockGATTClient) Subscribe(ctx context.Context, uuid string, notify func([]byte)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.handlers[uuid] = notify
+
+	go func() {
+		<-ctx.Done()
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		delete(m.handlers, uuid)
+	}()
+	return nil
+}
+
+func (m *MockGATTClient) Write(_ context.Context, uuid string, data []byte) error {
+	if m.OnWrite != nil {
+		m.OnWrite(uuid, data)
+	}
+	return nil
+}
+
+// Notify delivers a notification of the characteristic with the given UUID. It reports whether anyone was subscribed.
+func (m *MockGATTClient) Notify(uuid string, payload []byte) bool {
+	m.mu.Lock()
+	notify := m.handlers[uuid]
+	m.mu.Unlock()
+
+	if notify == nil {
+		return false
+	}
+	notify(payload)
+	return true
+}
@@ -0,0 +1,118 @@
+package ble
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/seagrayinc/gorow/pkg/pm5"
+)
+
+func TestClient(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	gatt := NewMockGATTClient()
+	var writes [][]byte
+	gatt.OnWrite = func(uuid string, data []byte) {
+		if uuid != SampleRateControl.UUID() {
+			t.Errorf("write to unexpected characteristic %s", uuid)
+		}
+		writes = append(writes, data)
+	}
+
+	c, err := Open(ctx, gatt)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if err := c.SetSampleRate(ctx, SampleRate100ms); err != nil {
+		t.Fatalf("SetSampleRate failed: %v", err)
+	}
+	if !slices.EqualFunc(writes, [][]byte{{0x03}}, slices.Equal) {
+		t.Errorf("writes mismatch: %v", writes)
+	}
+
+	if gatt.Notify(MultiplexedInformation.UUID(), []byte{0x31}) {
+		t.Error("multiplexed characteristic subscribed without WithMultiplexed")
+	}
+
+	// A drive followed by its stroke data and power completes a stroke.
+	strokeState := func(state byte) []byte {
+		b := make([]byte, 19)
+		b[8], b[10] = 1, state
+		return b
+	}
+	strokeData := append(make([]byte, 6), 0x8C, 0x53, 0xA7, 0x00, 0x16, 0x04, 0x0F, 0x05, 0x38, 0x03, 0xEE, 0x13, 0x01, 0x00)
+	power := []byte{0, 0, 0, 0xCB, 0x00}
+	gatt.Notify(GeneralStatus.UUID(), strokeState(pm5.StrokeStateDriving))
+	gatt.Notify(StrokeData.UUID(), strokeData)
+	gatt.Notify(AdditionalStrokeData.UUID(), power)
+	gatt.Notify(StrokeData.UUID(), []byte{0x01})
+
+	detector := pm5.NewStrokeDetector()
+	var strokes []pm5.Stroke
+	var decodeErr error
+	for decodeErr == nil {
+		select {
+		case e := <-c.Events():
+			if err, ok := e.(error); ok {
+				decodeErr = err
+			}
+			strokes = append(strokes, detector.Observe(time.Time{}, e)...)
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for events")
+		}
+	}
+
+	want := []pm5.Stroke{{
+		DriveCounter: 1,
+		DriveTime:    830 * time.Millisecond,
+		RecoveryTime: 1670 * time.Millisecond,
+		Length:       1.4,
+		Distance:     10.46,
+		PeakForce:    129.5,
+		AverageForce: 82.4,
+		Watts:        203,
+	}}
+	if !slices.Equal(strokes, want) {
+		t.Errorf("strokes mismatch:\ngot:  %+v\nwant: %+v", strokes, want)
+	}
+	if !errors.Is(decodeErr, ErrInvalidPayload) {
+		t.Errorf("got error %v, want ErrInvalidPayload", decodeErr)
+	}
+
+	cancel()
+	for range c.Events() {
+	}
+}
+
+func TestClientMultiplexed(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	gatt := NewMockGATTClient()
+	c, err := Open(ctx, gatt, WithMultiplexed(), WithEventBuffer(2))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if gatt.Notify(GeneralStatus.UUID(), make([]byte, 19)) {
+		t.Error("general status subscribed with WithMultiplexed")
+	}
+
+	// Additional status 1 decodes into three events, the first of which is dropped.
+	payload := []byte{0x32, 0, 0, 0, 0, 0, 24, 150, 0xE0, 0x2E}
+	if !gatt.Notify(MultiplexedInformation.UUID(), payload) {
+		t.Fatal("multiplexed characteristic not subscribed")
+	}
+	want := []any{
+		pm5.GetHRCurResponse{BeatsPerMinute: 150},
+		pm5.GetStroke500mPaceResponse{Per500m: 2 * time.Minute},
+	}
+	for _, w := range want {
+		if got := <-c.Events(); got != w {
+			t.Errorf("event mismatch: got %+v, want %+v", got, w)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package ble
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// chunkSize is the most bytes the PM accepts in a write to, and sends in a notification of, the control
+// characteristics.
+const chunkSize = 20
+
+// controlBuffer is the number of notifications a ControlChannel buffers until they are read.
+const controlBuffer = 64
+
+// ControlChannel carries CSAFE frames over the PM5 control service. It implements pm5.Channel, so every command of
+// pkg/pm5 can be sent over Bluetooth:
+//
+//	ch, err := ble.OpenControlChannel(ctx, gatt)
+//	p, err := pm5.Open(ctx, pm5.WithChannel(ch))
+type ControlChannel struct {
+	gatt   GATTClient
+	log    *slog.Logger
+	chunks chan []byte
+}
+
+// OpenControlChannel subscribes to the responses of the PM connected through gatt. Notifications are buffered until
+// read with Notifications. Once the buffer is full, further notifications are dropped with a warning rather than
+// holding up the Bluetooth stack; the response frames they belonged to are lost, and requests waiting for them time
+// out. Of the options, only WithLogger applies.
+func OpenControlChannel(ctx context.Context, gatt GATTClient, opts ...Option) (*ControlChannel, error) {
+	o := options{logger: slog.Default()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	c := &ControlChannel{gatt: gatt, log: o.logger, chunks: make(chan []byte, controlBuffer)}
+
+	err := gatt.Subscribe(ctx, ControlSend.UUID(), func(payload []byte) {
+		// The payload may be reused by the Bluetooth stack once notify returns.
+		chunk := append([]byte(nil), payload...)
+		select {
+		case c.chunks <- chunk:
+		default:
+			c.log.Warn("dropping control notification, buffer full", slog.Int("bytes", len(chunk)))
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ble: subscribing to %s: %w", ControlSend, err)
+	}
+	return c, nil
+}
+
+// Write writes a frame to the PM in chunks of 20 bytes.
+func (c *ControlChannel) Write(ctx context.Context, frame []byte) error {
+	for len(frame) > 0 {
+		n := min(chunkSize, len(frame))
+		if err := c.gatt.Write(ctx, ControlReceive.UUID(), frame[:n]); err != nil {
+			return fmt.Errorf("ble: writing to %s: %w", ControlReceive, err)
+		}
+		frame = frame[n:]
+	}
+	return nil
+}
+
+// Notifications returns the payloads of the notifications received from the PM. The channel is closed when ctx is
+// done.
+func (c *ControlChannel) Notifications(ctx context.Context) <-chan []byte {
+	out := make(chan []byte)
+
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case chunk := <-c.chunks:
+				select {
+				case out <- chunk:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+// Close does nothing; the connection belongs to the GATTClient.
+func (c *ControlChannel) Close() error {
+	return nil
+}
@@ -0,0 +1,137 @@
+package ble
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/seagrayinc/gorow/pkg/pm5"
+)
+
+// emulatedGATT returns a MockGATTClient whose control service is answered by e, with frames split into chunks of 20
+// bytes in both directions.
+func emulatedGATT(t *testing.T, ctx context.Context, e *pm5.Emulator) *MockGATTClient {
+	t.Helper()
+
+	gatt := NewMockGATTClient()
+	var mu sync.Mutex
+	var pending []byte
+	gatt.OnWrite = func(uuid string, data []byte) {
+		if uuid != ControlReceive.UUID() {
+			t.Errorf("write to unexpected characteristic %s", uuid)
+		}
+		if len(data) > chunkSize {
+			t.Errorf("write of %d bytes exceeds %d", len(data), chunkSize)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		pending = append(pending, data...)
+		if data[len(data)-1] == 0xF2 {
+			go e.WriteReport(ctx, pm5.Report{ID: 0x02, Data: pending})
+			pending = nil
+		}
+	}
+
+	go func() {
+		for r := range e.PollReports(ctx) {
+			frame := r.Data[:bytes.LastIndexByte(r.Data, 0xF2)+1]
+			for len(frame) > 0 {
+				n := min(chunkSize, len(frame))
+				gatt.Notify(ControlSend.UUID(), frame[:n])
+				frame = frame[n:]
+			}
+		}
+	}()
+
+	return gatt
+}
+
+func TestControlChannel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	e := pm5.NewEmulator(pm5.EmulatorConfig{Manual: true})
+	ch, err := OpenControlChannel(ctx, emulatedGATT(t, ctx, e))
+	if err != nil {
+		t.Fatalf("OpenControlChannel failed: %v", err)
+	}
+	p, err := pm5.Open(ctx, pm5.WithChannel(ch))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	serial, err := pm5.Query[pm5.GetSerialResponse](ctx, p, pm5.GetSerial())
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if serial.SerialNumber != "430000000" {
+		t.Errorf("serial number: got %q, want 430000000", serial.SerialNumber)
+	}
+
+	workout := pm5.Workout{Intervals: []pm5.Interval{
+		{Duration: pm5.Meters(250), Rest: time.Minute},
+		{Duration: pm5.Time(time.Minute)},
+	}}
+	if err := pm5.ProgramWorkout(ctx, p, workout); err != nil {
+		t.Fatalf("ProgramWorkout failed: %v", err)
+	}
+	e.Advance(5 * time.Minute)
+
+	summary, err := pm5.FetchWorkoutSummary(ctx, p)
+	if err != nil {
+		t.Fatalf("FetchWorkoutSummary failed: %v", err)
+	}
+	if summary.WorkoutType != pm5.WorkoutTypeVariableInterval || len(summary.Intervals) != 1 {
+		t.Fatalf("summary mismatch: %+v", summary)
+	}
+	if got := summary.Intervals[0]; got.Time != time.Minute || got.Meters != 250 {
+		t.Errorf("last interval mismatch: %+v", got)
+	}
+}
+
+func TestControlChannelOverflow(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	gatt := NewMockGATTClient()
+	var logs bytes.Buffer
+	ch, err := OpenControlChannel(ctx, gatt, WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+	if err != nil {
+		t.Fatalf("OpenControlChannel failed: %v", err)
+	}
+
+	// Notifications beyond the buffer are dropped instead of blocking the Bluetooth stack.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := range controlBuffer + 8 {
+			gatt.Notify(ControlSend.UUID(), []byte{byte(i)})
+		}
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		t.Fatal("notify blocked on a full buffer")
+	}
+	if got := strings.Count(logs.String(), "dropping control notification"); got != 8 {
+		t.Errorf("logged %d dropped notifications, want 8", got)
+	}
+
+	notifications := ch.Notifications(ctx)
+	for i := range controlBuffer {
+		if got := <-notifications; !bytes.Equal(got, []byte{byte(i)}) {
+			t.Fatalf("notification %d: got % X", i, got)
+		}
+	}
+
+	// Once read, the buffer takes notifications again.
+	gatt.Notify(ControlSend.UUID(), []byte{0xF2})
+	if got := <-notifications; !bytes.Equal(got, []byte{0xF2}) {
+		t.Errorf("notification after overflow: got % X", got)
+	}
+}
@@ -0,0 +1,324 @@
+package ble
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/seagrayinc/gorow/pkg/pm5"
+)
+
+// ErrInvalidPayload is returned when a notification is too short for its characteristic.
+var ErrInvalidPayload = errors.New("invalid payload")
+
+// Decoder turns notifications of the rowing service into pkg/pm5 types. Most characteristics decode on their own;
+// the Decoder keeps the state needed to stitch force curves from several notifications and to collect the splits or
+// intervals of a workout for its summary. A Decoder is not safe for concurrent use.
+//
+// Notifications decode into:
+//
+//   - General status: pm5.GetWorkTimeResponse, pm5.GetWorkDistanceResponse, pm5.GetWorkoutTypeResponse,
+//     pm5.GetWorkoutStateResponse, pm5.GetStrokeStateResponse and pm5.GetDragFactorResponse
+//   - Additional status 1: pm5.GetStrokeRateResponse, pm5.GetHRCurResponse and pm5.GetStroke500mPaceResponse
+//   - Additional status 2: pm5.GetWorkoutIntervalCountResponse, pm5.GetCaloriesResponse,
+//     pm5.GetSplitAvg500mPaceResponse, pm5.GetLastSplitTimeResponse and pm5.GetLastSplitDistanceResponse
+//   - Stroke data: pm5.GetStrokeStatsResponse
+//   - Additional stroke data: pm5.GetPowerResponse
+//   - Split/interval data: pm5.GetLastSplitTimeResponse, pm5.GetLastSplitDistanceResponse and pm5.GetRestTimeResponse
+//   - Additional split/interval data: pm5.GetSplitAvgStrokeRateResponse, pm5.GetAvgHeartRateResponse and
+//     pm5.GetSplitAvg500mPaceResponse
+//   - End of workout summary: pm5.WorkoutSummary
+//   - Force curve data: pm5.ForceCurve, once every notification of the curve has been received, with the drive
+//     counter of the last stroke data
+//
+// Other characteristics decode into no events.
+type Decoder struct {
+	strokeCount int // Drive counter of the last stroke data, for force curves
+
+	curve     []int // Force curve samples received so far
+	curveNext int   // Sequence number of the next force curve notification
+	curveLeft int   // Notifications of the force curve still to come
+
+	intervals []pm5.IntervalSummary // Splits or intervals of the current workout
+	interval  int                   // Number of the last split or interval in intervals
+}
+
+// NewDecoder returns a Decoder.
+func NewDecoder() *Decoder {
+	return &Decoder{}
+}
+
+// Decode decodes the payload of a notification of the given characteristic.
+func (d *Decoder) Decode(c Characteristic, payload []byte) ([]any, error) {
+	if c == MultiplexedInformation {
+		if len(payload) < 1 {
+			return nil, fmt.Errorf("ble: %s: %w", c, ErrInvalidPayload)
+		}
+		c, payload = Characteristic(payload[0]), payload[1:]
+		if c == StrokeData {
+			return d.decodeMultiplexedStrokeData(payload)
+		}
+	}
+
+	var events []any
+	var err error
+	switch c {
+	case GeneralStatus:
+		events, err = decodeGeneralStatus(payload)
+	case AdditionalStatus1:
+		events, err = decodeAdditionalStatus1(payload)
+	case AdditionalStatus2:
+		events, err = decodeAdditionalStatus2(payload)
+	case StrokeData:
+		events, err = d.decodeStrokeData(payload)
+	case AdditionalStrokeData:
+		events, err = decodeAdditionalStrokeData(payload)
+	case SplitIntervalData:
+		events, err = d.decodeSplitIntervalData(payload)
+	case AdditionalSplitIntervalData:
+		events, err = d.decodeAdditionalSplitIntervalData(payload)
+	case EndOfWorkoutSummary:
+		events, err = d.decodeEndOfWorkoutSummary(payload)
+	case ForceCurveData:
+		events, err = d.decodeForceCurveData(payload)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ble: %s: %w", c, err)
+	}
+	return events, nil
+}
+
+func checkLength(b []byte, n int) error {
+	if len(b) < n {
+		return fmt.Errorf("%w: expected at least %d bytes, got %d", ErrInvalidPayload, n, len(b))
+	}
+	return nil
+}
+
+func uint16le(b []byte) int {
+	return int(b[0]) | int(b[1])<<8
+}
+
+func uint24le(b []byte) int {
+	return int(b[0]) | int(b[1])<<8 | int(b[2])<<16
+}
+
+// hundredths converts a count of 0.01 s to a duration.
+func hundredths(n int) time.Duration {
+	return time.Duration(n) * 10 * time.Millisecond
+}
+
+// tenths converts a count of 0.1 s to a duration.
+func tenths(n int) time.Duration {
+	return time.Duration(n) * 100 * time.Millisecond
+}
+
+// heartRate converts a heart rate of the PM, which is 255 without a belt, to beats per minute or zero.
+func heartRate(b byte) int {
+	if b == 255 {
+		return 0
+	}
+	return int(b)
+}
+
+func decodeGeneralStatus(b []byte) ([]any, error) {
+	if err := checkLength(b, 19); err != nil {
+		return nil, err
+	}
+
+	state := int(b[8])
+	return []any{
+		pm5.GetWorkTimeResponse{WorkTime: hundredths(uint24le(b[0:3]))},
+		pm5.GetWorkDistanceResponse{Meters: float64(uint24le(b[3:6])) / 10},
+		pm5.GetWorkoutTypeResponse{WorkoutType: pm5.WorkoutType(b[6])},
+		pm5.GetWorkoutStateResponse{WorkoutState: state, WorkoutStateString: pm5.WorkoutStateMap[state]},
+		pm5.GetStrokeStateResponse{StrokeState: int(b[10])},
+		pm5.GetDragFactorResponse{DragFactor: int(b[18])},
+	}, nil
+}
+
+func decodeAdditionalStatus1(b []byte) ([]any, error) {
+	if err := checkLength(b, 9); err != nil {
+		return nil, err
+	}
+
+	return []any{
+		pm5.GetStrokeRateResponse{StrokesPerMinute: int(b[5])},
+		pm5.GetHRCurResponse{BeatsPerMinute: int(b[6])},
+		pm5.GetStroke500mPaceResponse{Per500m: hundredths(uint16le(b[7:9]))},
+	}, nil
+}
+
+func decodeAdditionalStatus2(b []byte) ([]any, error) {
+	if err := checkLength(b, 10); err != nil {
+		return nil, err
+	}
+
+	events := []any{
+		pm5.GetWorkoutIntervalCountResponse{IntervalCount: int(b[3])},
+		pm5.GetCaloriesResponse{Calories: uint16le(b[6:8])},
+		pm5.GetSplitAvg500mPaceResponse{Per500m: hundredths(uint16le(b[8:10]))},
+	}
+	// The multiplexed notification has no room for the last split distance.
+	if len(b) >= 17 {
+		events = append(events, pm5.GetLastSplitTimeResponse{SplitTime: tenths(uint24le(b[14:17]))})
+	}
+	if len(b) >= 20 {
+		events = append(events, pm5.GetLastSplitDistanceResponse{Meters: uint24le(b[17:20])})
+	}
+	return events, nil
+}
+
+func (d *Decoder) decodeStrokeData(b []byte) ([]any, error) {
+	if err := checkLength(b, 20); err != nil {
+		return nil, err
+	}
+
+	stats := strokeStats(b)
+	stats.WorkPerStroke = uint16le(b[16:18])
+	stats.DriveCounter = uint16le(b[18:20])
+	d.strokeCount = stats.DriveCounter
+	return []any{stats}, nil
+}
+
+// decodeMultiplexedStrokeData decodes the multiplexed form of the stroke data, which leaves out the work per stroke.
+func (d *Decoder) decodeMultiplexedStrokeData(b []byte) ([]any, error) {
+	if err := checkLength(b, 18); err != nil {
+		return nil, fmt.Errorf("ble: %s: %w", StrokeData, err)
+	}
+
+	stats := strokeStats(b)
+	stats.DriveCounter = uint16le(b[16:18])
+	d.strokeCount = stats.DriveCounter
+	return []any{stats}, nil
+}
+
+// strokeStats decodes the fields shared by both forms of the stroke data. Their units match those of the CSAFE
+// stroke stats.
+func strokeStats(b []byte) pm5.GetStrokeStatsResponse {
+	return pm5.GetStrokeStatsResponse{
+		StrokeLength:       int(b[6]),
+		StrokeDriveTime:    int(b[7]),
+		StrokeRecoveryTime: uint16le(b[8:10]),
+		StrokeDistance:     uint16le(b[10:12]),
+		PeakDriveForce:     uint16le(b[12:14]),
+		AverageDriveForce:  uint16le(b[14:16]),
+	}
+}
+
+func decodeAdditionalStrokeData(b []byte) ([]any, error) {
+	if err := checkLength(b, 5); err != nil {
+		return nil, err
+	}
+
+	return []any{pm5.GetPowerResponse{StrokeWatts: uint16le(b[3:5]), UnitsSpecifier: pm5.PowerUnitsWatts}}, nil
+}
+
+// split returns the summary of the split or interval with the given number, adding it if it is new. A number lower
+// than the last one means a new workout started.
+func (d *Decoder) split(number int) *pm5.IntervalSummary {
+	if number < d.interval {
+		d.intervals = nil
+	}
+	if len(d.intervals) == 0 || number != d.interval {
+		d.intervals = append(d.intervals, pm5.IntervalSummary{})
+		d.interval = number
+	}
+	return &d.intervals[len(d.intervals)-1]
+}
+
+func (d *Decoder) decodeSplitIntervalData(b []byte) ([]any, error) {
+	if err := checkLength(b, 18); err != nil {
+		return nil, err
+	}
+
+	splitTime := tenths(uint24le(b[6:9]))
+	meters := uint24le(b[9:12])
+	rest := time.Duration(uint16le(b[12:14])) * time.Second
+
+	s := d.split(int(b[17]))
+	s.Time, s.Meters, s.Rest = splitTime, meters, rest
+
+	return []any{
+		pm5.GetLastSplitTimeResponse{SplitTime: splitTime},
+		pm5.GetLastSplitDistanceResponse{Meters: meters},
+		pm5.GetRestTimeResponse{RestTime: rest},
+	}, nil
+}
+
+func (d *Decoder) decodeAdditionalSplitIntervalData(b []byte) ([]any, error) {
+	if err := checkLength(b, 18); err != nil {
+		return nil, err
+	}
+
+	strokeRate := int(b[3])
+	hr := heartRate(b[4])
+	pace := tenths(uint16le(b[6:8]))
+
+	s := d.split(int(b[17]))
+	s.StrokeRate, s.HeartRate, s.Pace = strokeRate, hr, pace
+
+	return []any{
+		pm5.GetSplitAvgStrokeRateResponse{StrokesPerMinute: strokeRate},
+		pm5.GetAvgHeartRateResponse{BeatsPerMinute: hr},
+		pm5.GetSplitAvg500mPaceResponse{Per500m: pace},
+	}, nil
+}
+
+// decodeEndOfWorkoutSummary returns the summary of the workout with the splits or intervals received during it. A
+// workout without any is summarized as a single interval.
+func (d *Decoder) decodeEndOfWorkoutSummary(b []byte) ([]any, error) {
+	if err := checkLength(b, 18); err != nil {
+		return nil, err
+	}
+
+	summary := pm5.WorkoutSummary{WorkoutType: pm5.WorkoutType(b[17]), Intervals: d.intervals}
+	if len(summary.Intervals) == 0 {
+		whole := pm5.IntervalSummary{
+			Time:       hundredths(uint24le(b[4:7])),
+			Meters:     uint24le(b[7:10]) / 10,
+			StrokeRate: int(b[10]),
+			HeartRate:  heartRate(b[12]),
+		}
+		// The multiplexed notification has no room for the average pace.
+		if len(b) >= 20 {
+			whole.Pace = tenths(uint16le(b[18:20]))
+		}
+		summary.Intervals = []pm5.IntervalSummary{whole}
+	}
+
+	d.intervals, d.interval = nil, 0
+	return []any{summary}, nil
+}
+
+// decodeForceCurveData collects the notifications of a force curve. The first byte holds the number of notifications
+// of the curve in its high nibble and the number of 16-bit samples in this one in its low nibble; the second byte is
+// a sequence number. A notification out of sequence starts the curve afresh.
+func (d *Decoder) decodeForceCurveData(b []byte) ([]any, error) {
+	if err := checkLength(b, 2); err != nil {
+		return nil, err
+	}
+	total, words, seq := int(b[0]>>4), int(b[0]&0x0F), int(b[1])
+	if total == 0 {
+		return nil, fmt.Errorf("%w: force curve of no notifications", ErrInvalidPayload)
+	}
+	if err := checkLength(b, 2+2*words); err != nil {
+		return nil, err
+	}
+
+	if d.curveLeft == 0 || seq != d.curveNext {
+		d.curve, d.curveLeft = nil, total
+	}
+	for i := range words {
+		d.curve = append(d.curve, uint16le(b[2+2*i:]))
+	}
+	d.curveNext = (seq + 1) & 0xFF
+	d.curveLeft--
+
+	if d.curveLeft > 0 {
+		return nil, nil
+	}
+	curve := pm5.ForceCurve{DriveCounter: d.strokeCount, Samples: d.curve}
+	d.curve = nil
+	return []any{curve}, nil
+}
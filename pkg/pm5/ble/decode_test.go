@@ -0,0 +1,275 @@
+package ble
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/seagrayinc/gorow/pkg/pm5"
+)
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name    string
+		char    Characteristic
+		payload []byte
+		want    []any
+	}{
+		{
+			name: "general status",
+			char: GeneralStatus,
+			payload: []byte{
+				0x39, 0x30, 0x00, // 123.45 s
+				0x39, 0x30, 0x00, // 1234.5 m
+				0x01, 0xFF, 0x01, 0x01, 0x02,
+				0xD2, 0x04, 0x00, // Total work distance
+				0x00, 0x00, 0x00, 0x00,
+				0x78,
+			},
+			want: []any{
+				pm5.GetWorkTimeResponse{WorkTime: 123450 * time.Millisecond},
+				pm5.GetWorkDistanceResponse{Meters: 1234.5},
+				pm5.GetWorkoutTypeResponse{WorkoutType: pm5.WorkoutTypeJustRowSplits},
+				pm5.GetWorkoutStateResponse{WorkoutState: 1, WorkoutStateString: "Workout row"},
+				pm5.GetStrokeStateResponse{StrokeState: pm5.StrokeStateDriving},
+				pm5.GetDragFactorResponse{DragFactor: 120},
+			},
+		},
+		{
+			name: "additional status 1",
+			char: AdditionalStatus1,
+			payload: []byte{
+				0x39, 0x30, 0x00,
+				0x10, 0x10, // Speed
+				0x18, 0x96,
+				0xE0, 0x2E, // 120.00 s
+				0xE0, 0x2E,
+				0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00,
+			},
+			want: []any{
+				pm5.GetStrokeRateResponse{StrokesPerMinute: 24},
+				pm5.GetHRCurResponse{BeatsPerMinute: 150},
+				pm5.GetStroke500mPaceResponse{Per500m: 2 * time.Minute},
+			},
+		},
+		{
+			name: "additional status 2",
+			char: AdditionalStatus2,
+			payload: []byte{
+				0x39, 0x30, 0x00,
+				0x02,
+				0xCB, 0x00,
+				0x4B, 0x00, // 75 cal
+				0xD4, 0x2E, // 119.88 s
+				0xCC, 0x00, 0x00, 0x00,
+				0xB0, 0x04, 0x00, // 120.0 s
+				0xF4, 0x01, 0x00, // 500 m
+			},
+			want: []any{
+				pm5.GetWorkoutIntervalCountResponse{IntervalCount: 2},
+				pm5.GetCaloriesResponse{Calories: 75},
+				pm5.GetSplitAvg500mPaceResponse{Per500m: 119880 * time.Millisecond},
+				pm5.GetLastSplitTimeResponse{SplitTime: 2 * time.Minute},
+				pm5.GetLastSplitDistanceResponse{Meters: 500},
+			},
+		},
+		{
+			name: "stroke data",
+			char: StrokeData,
+			payload: []byte{
+				0x39, 0x30, 0x00, 0x39, 0x30, 0x00,
+				0x8C,       // 1.40 m
+				0x53,       // 0.83 s
+				0xA7, 0x00, // 1.67 s
+				0x16, 0x04, // 10.46 m
+				0x0F, 0x05, // 129.5 lbs
+				0x38, 0x03, // 82.4 lbs
+				0xEE, 0x13, // 510.2 J
+				0x2A, 0x00, // Stroke 42
+			},
+			want: []any{
+				pm5.GetStrokeStatsResponse{
+					StrokeDistance:     1046,
+					StrokeDriveTime:    83,
+					StrokeRecoveryTime: 167,
+					StrokeLength:       140,
+					DriveCounter:       42,
+					PeakDriveForce:     1295,
+					AverageDriveForce:  824,
+					WorkPerStroke:      5102,
+				},
+			},
+		},
+		{
+			name:    "additional stroke data",
+			char:    AdditionalStrokeData,
+			payload: []byte{0x39, 0x30, 0x00, 0xCB, 0x00, 0x00, 0x00, 0x2A, 0x00, 0, 0, 0, 0, 0, 0},
+			want:    []any{pm5.GetPowerResponse{StrokeWatts: 203, UnitsSpecifier: pm5.PowerUnitsWatts}},
+		},
+		{
+			name: "multiplexed stroke data",
+			char: MultiplexedInformation,
+			payload: []byte{
+				0x35,
+				0x39, 0x30, 0x00, 0x39, 0x30, 0x00,
+				0x8C, 0x53, 0xA7, 0x00, 0x16, 0x04, 0x0F, 0x05, 0x38, 0x03,
+				0x2A, 0x00,
+			},
+			want: []any{
+				pm5.GetStrokeStatsResponse{
+					StrokeDistance:     1046,
+					StrokeDriveTime:    83,
+					StrokeRecoveryTime: 167,
+					StrokeLength:       140,
+					DriveCounter:       42,
+					PeakDriveForce:     1295,
+					AverageDriveForce:  824,
+				},
+			},
+		},
+		{
+			name: "multiplexed additional status 2",
+			char: MultiplexedInformation,
+			payload: []byte{
+				0x33,
+				0x39, 0x30, 0x00, 0x02, 0xCB, 0x00, 0x4B, 0x00, 0xD4, 0x2E, 0xCC, 0x00, 0x00, 0x00,
+				0xB0, 0x04, 0x00, 0xF4, 0x01,
+			},
+			want: []any{
+				pm5.GetWorkoutIntervalCountResponse{IntervalCount: 2},
+				pm5.GetCaloriesResponse{Calories: 75},
+				pm5.GetSplitAvg500mPaceResponse{Per500m: 119880 * time.Millisecond},
+				pm5.GetLastSplitTimeResponse{SplitTime: 2 * time.Minute},
+			},
+		},
+		{
+			name:    "unhandled characteristic",
+			char:    HeartRateBeltInformation,
+			payload: []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewDecoder().Decode(tt.char, tt.payload)
+			if err != nil {
+				t.Fatalf("Decode failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("events mismatch:\ngot:  %+v\nwant: %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeShortPayload(t *testing.T) {
+	for _, c := range []Characteristic{GeneralStatus, StrokeData, SplitIntervalData, ForceCurveData, MultiplexedInformation} {
+		if _, err := NewDecoder().Decode(c, nil); !errors.Is(err, ErrInvalidPayload) {
+			t.Errorf("%s: got %v, want ErrInvalidPayload", c, err)
+		}
+	}
+}
+
+func TestDecodeWorkoutSummary(t *testing.T) {
+	d := NewDecoder()
+
+	splitData := func(number byte, tenths, meters, rest int) []byte {
+		return []byte{
+			0, 0, 0, 0, 0, 0,
+			byte(tenths), byte(tenths >> 8), 0,
+			byte(meters), byte(meters >> 8), 0,
+			byte(rest), 0,
+			0, 0, 0, number,
+		}
+	}
+	additionalSplitData := func(number, strokeRate, hr byte, paceTenths int) []byte {
+		return []byte{
+			0, 0, 0, strokeRate, hr, 0,
+			byte(paceTenths), byte(paceTenths >> 8),
+			0, 0, 0, 0, 0, 0, 0, 0, 0, number, 0,
+		}
+	}
+
+	notifications := []struct {
+		char    Characteristic
+		payload []byte
+	}{
+		{SplitIntervalData, splitData(1, 2400, 1000, 120)},
+		{AdditionalSplitIntervalData, additionalSplitData(1, 26, 160, 1200)},
+		{SplitIntervalData, splitData(2, 2380, 1000, 0)},
+		{AdditionalSplitIntervalData, additionalSplitData(2, 27, 255, 1190)},
+	}
+	for _, n := range notifications {
+		if _, err := d.Decode(n.char, n.payload); err != nil {
+			t.Fatalf("Decode failed: %v", err)
+		}
+	}
+
+	summary := make([]byte, 20)
+	summary[17] = byte(pm5.WorkoutTypeVariableInterval)
+	got, err := d.Decode(EndOfWorkoutSummary, summary)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+
+	want := []any{pm5.WorkoutSummary{
+		WorkoutType: pm5.WorkoutTypeVariableInterval,
+		Intervals: []pm5.IntervalSummary{
+			{Time: 4 * time.Minute, Meters: 1000, Pace: 2 * time.Minute, StrokeRate: 26, HeartRate: 160, Rest: 2 * time.Minute},
+			{Time: 238 * time.Second, Meters: 1000, Pace: 119 * time.Second, StrokeRate: 27},
+		},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("summary mismatch:\ngot:  %+v\nwant: %+v", got, want)
+	}
+
+	// A workout without splits is summarized as a whole.
+	got, err = d.Decode(EndOfWorkoutSummary, []byte{
+		0, 0, 0, 0,
+		0xE0, 0x2E, 0x00, // 120.00 s
+		0x88, 0x13, 0x00, // 500.0 m
+		24, 150, 148, 0, 0, 120, 0,
+		byte(pm5.WorkoutTypeFixedDistanceNoSplits),
+		0xB0, 0x04, // 120.0 s
+	})
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	want = []any{pm5.WorkoutSummary{
+		WorkoutType: pm5.WorkoutTypeFixedDistanceNoSplits,
+		Intervals:   []pm5.IntervalSummary{{Time: 2 * time.Minute, Meters: 500, Pace: 2 * time.Minute, StrokeRate: 24, HeartRate: 148}},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("summary mismatch:\ngot:  %+v\nwant: %+v", got, want)
+	}
+}
+
+func TestDecodeForceCurve(t *testing.T) {
+	d := NewDecoder()
+	if _, err := d.Decode(StrokeData, append(make([]byte, 18), 0x07, 0x00)); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+
+	notifications := [][]byte{
+		{0x33, 0x05, 10, 0, 20, 0, 30, 0},
+		// Out of sequence: the curve starts again
+		{0x33, 0x07, 10, 0, 40, 0, 80, 0},
+		{0x33, 0x08, 120, 0, 0x2C, 0x01, 100, 0},
+		{0x32, 0x09, 50, 0, 5, 0},
+	}
+	var got []any
+	for _, n := range notifications {
+		events, err := d.Decode(ForceCurveData, n)
+		if err != nil {
+			t.Fatalf("Decode failed: %v", err)
+		}
+		got = append(got, events...)
+	}
+
+	want := []any{pm5.ForceCurve{DriveCounter: 7, Samples: []int{10, 40, 80, 120, 300, 100, 50, 5}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("force curve mismatch:\ngot:  %+v\nwant: %+v", got, want)
+	}
+}
@@ -0,0 +1,36 @@
+package pm5
+
+import (
+	"io"
+
+	"github.com/seagrayinc/gorow/internal/hid"
+)
+
+// Recorder is a Device that captures all reports exchanged with another device to a compact file format, with their
+// times and directions. See WithRecording.
+type Recorder = hid.Recorder
+
+// Replayer is a Device that plays back the reports the PM sent in a capture written by a Recorder, in real time or
+// with scaled timing. Pass it to WithDevice to reproduce a session without the hardware.
+type Replayer = hid.Replayer
+
+// CaptureRecord is a single report of a capture.
+type CaptureRecord = hid.CaptureRecord
+
+// ErrInvalidCapture is returned when reading a capture that was not written by a Recorder.
+var ErrInvalidCapture = hid.ErrInvalidCapture
+
+// NewRecorder returns a Recorder capturing the reports exchanged with dev to w.
+func NewRecorder(dev Device, w io.Writer) *Recorder {
+	return hid.NewRecorder(dev, w)
+}
+
+// NewReplayer reads a capture written by a Recorder and returns a Replayer for it.
+func NewReplayer(r io.Reader) (*Replayer, error) {
+	return hid.NewReplayer(r)
+}
+
+// ReadCapture reads all records of a capture written by a Recorder.
+func ReadCapture(r io.Reader) ([]CaptureRecord, error) {
+	return hid.ReadCapture(r)
+}
@@ -0,0 +1,53 @@
+package pm5
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	hid2 "github.com/seagrayinc/gorow/internal/hid"
+)
+
+func TestRecordReplay(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// Record a query answered by a mock PM...
+	mockHID := hid2.NewMockHID()
+	mockHID.OnWrite = func(hid2.Report) {
+		go mockHID.Emit(responseReport(0x01, 0x1A, 0x03, 0x8D, 0x01, 0x01))
+	}
+	var capture bytes.Buffer
+	p, err := Open(ctx, WithDevice(mockHID), WithRecording(&capture))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if _, err := Query[GetWorkoutStateResponse](ctx, p, GetWorkoutState()); err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	// ...and answer the same query from the capture.
+	replayer, err := NewReplayer(&capture)
+	if err != nil {
+		t.Fatalf("NewReplayer failed: %v", err)
+	}
+	replayer.Scale, replayer.Lockstep = 0, true
+
+	p, err = Open(ctx, WithDevice(replayer))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	got, err := Query[GetWorkoutStateResponse](ctx, p, GetWorkoutState())
+	if err != nil {
+		t.Fatalf("replayed Query failed: %v", err)
+	}
+
+	want := GetWorkoutStateResponse{WorkoutState: 1, WorkoutStateString: "Workout row"}
+	if got != want {
+		t.Errorf("response mismatch:\ngot:  %+v\nwant: %+v", got, want)
+	}
+}
@@ -1,14 +1,54 @@
 package pm5
 
 import (
+	"encoding/binary"
 	"encoding/hex"
+	"errors"
+	"fmt"
 	"log/slog"
 
 	"github.com/seagrayinc/gorow/internal/csafe"
 )
 
+// ErrMalformedResponse is returned when a command response is too short or otherwise cannot be decoded.
+var ErrMalformedResponse = errors.New("malformed response")
+
 type parserFunc func([]byte) (any, error)
 
+// checkLength returns an error if a response carries fewer than n data bytes.
+func checkLength(b []byte, n int) error {
+	if len(b) < n {
+		return fmt.Errorf("%w: got %d data bytes, want %d", ErrMalformedResponse, len(b), n)
+	}
+	return nil
+}
+
+// blockLength is the largest block of sample data the PM returns per request, in bytes.
+const blockLength = 32
+
+// parseBlock decodes a block of 16-bit little-endian samples prefixed with the number of bytes read, as returned by
+// CSAFE_PM_GET_FORCEPLOTDATA and CSAFE_PM_GET_HEARTBEATDATA.
+func parseBlock(b []byte) (int, []int, error) {
+	if err := checkLength(b, 1); err != nil {
+		return 0, nil, err
+	}
+
+	n := int(b[0])
+	if n > blockLength || n%2 != 0 {
+		return 0, nil, fmt.Errorf("%w: invalid block length %d", ErrMalformedResponse, n)
+	}
+	if err := checkLength(b[1:], n); err != nil {
+		return 0, nil, err
+	}
+
+	samples := make([]int, 0, n/2)
+	for i := 1; i < 1+n; i += 2 {
+		samples = append(samples, int(binary.LittleEndian.Uint16(b[i:i+2])))
+	}
+
+	return n, samples, nil
+}
+
 // wrappedParser is a helper to convert a typed parser function into a generic parserFunc.
 func wrappedParser[T any](f func([]byte) (T, error)) parserFunc {
 	return func(b []byte) (any, error) {
@@ -17,21 +57,48 @@ func wrappedParser[T any](f func([]byte) (T, error)) parserFunc {
 }
 
 var (
+	// parserMap holds the parsers for standard CSAFE command responses.
 	parserMap = map[byte]parserFunc{
-		csafe_GETVERSION_CMD:      wrappedParser(parseGetVersionResponse),
-		csafe_GETPOWER_CMD:        wrappedParser(parseGetPowerResponse),
-		csafe_GETID_CMD:           wrappedParser(parseGetIDResponse),
-		csafe_GETUNITS_CMD:        wrappedParser(parseGetUnitsResponse),
-		csafe_GETSERIAL_CMD:       wrappedParser(parseGetSerialResponse),
-		csafe_GETODOMETER_CMD:     wrappedParser(parseGetOdometerResponse),
-		csafe_GETERRORCODE_CMD:    wrappedParser(parseGetErrorCodeResponse),
-		csafe_PM_GET_STROKESTATS:  wrappedParser(parseGetStrokeStatsResponse),
-		csafe_PM_GET_STROKESTATE:  wrappedParser(parseGetStrokeStateResponse),
-		csafe_PM_GET_WORKOUTSTATE: wrappedParser(parseGetWorkoutStateResponse),
+		csafe_GETVERSION_CMD:    wrappedParser(parseGetVersionResponse),
+		csafe_GETPOWER_CMD:      wrappedParser(parseGetPowerResponse),
+		csafe_GETID_CMD:         wrappedParser(parseGetIDResponse),
+		csafe_GETUNITS_CMD:      wrappedParser(parseGetUnitsResponse),
+		csafe_GETSERIAL_CMD:     wrappedParser(parseGetSerialResponse),
+		csafe_GETODOMETER_CMD:   wrappedParser(parseGetOdometerResponse),
+		csafe_GETERRORCODE_CMD:  wrappedParser(parseGetErrorCodeResponse),
+		csafe_GETHRCUR_CMD:      wrappedParser(parseGetHRCurResponse),
+		csafe_GETTWORK_CMD:      wrappedParser(parseGetTWorkResponse),
+		csafe_GETHORIZONTAL_CMD: wrappedParser(parseGetHorizontalResponse),
+		csafe_GETCALORIES_CMD:   wrappedParser(parseGetCaloriesResponse),
+		csafe_GETPACE_CMD:       wrappedParser(parseGetPaceResponse),
+		csafe_GETCADENCE_CMD:    wrappedParser(parseGetCadenceResponse),
+	}
+
+	// pmParserMap holds the parsers for PM-specific command responses, which arrive nested in a wrapper response. The
+	// PM-specific command IDs overlap with the standard ones, so they are kept apart.
+	pmParserMap = map[byte]parserFunc{
+		csafe_PM_GET_STROKESTATS:          wrappedParser(parseGetStrokeStatsResponse),
+		csafe_PM_GET_STROKESTATE:          wrappedParser(parseGetStrokeStateResponse),
+		csafe_PM_GET_WORKOUTSTATE:         wrappedParser(parseGetWorkoutStateResponse),
+		csafe_PM_GET_FORCEPLOTDATA:        wrappedParser(parseGetForcePlotDataResponse),
+		csafe_PM_GET_HEARTBEATDATA:        wrappedParser(parseGetHeartbeatDataResponse),
+		csafe_PM_GET_WORKTIME:             wrappedParser(parseGetWorkTimeResponse),
+		csafe_PM_GET_WORKDISTANCE:         wrappedParser(parseGetWorkDistanceResponse),
+		csafe_PM_GET_STROKE500MPACE:       wrappedParser(parseGetStroke500mPaceResponse),
+		csafe_PM_GET_STROKERATE:           wrappedParser(parseGetStrokeRateResponse),
+		csafe_PM_GET_DRAGFACTOR:           wrappedParser(parseGetDragFactorResponse),
+		csafe_PM_GET_WORKOUTTYPE:          wrappedParser(parseGetWorkoutTypeResponse),
+		csafe_PM_GET_WORKOUTINTERVALCOUNT: wrappedParser(parseGetWorkoutIntervalCountResponse),
+		csafe_PM_GET_LASTSPLITTIME:        wrappedParser(parseGetLastSplitTimeResponse),
+		csafe_PM_GET_LASTSPLITDISTANCE:    wrappedParser(parseGetLastSplitDistanceResponse),
+		csafe_PM_GET_SPLITAVG500MPACE:     wrappedParser(parseGetSplitAvg500mPaceResponse),
+		csafe_PM_GET_SPLITAVGSTROKERATE:   wrappedParser(parseGetSplitAvgStrokeRateResponse),
+		csafe_PM_GET_AVGHEARTRATE:         wrappedParser(parseGetAvgHeartRateResponse),
+		csafe_PM_GET_RESTTIME:             wrappedParser(parseGetRestTimeResponse),
 	}
 )
 
-func parseResponses(f csafe.ExtendedResponseFrame) ([]any, error) {
+func parseResponses(log *slog.Logger, f csafe.ExtendedResponseFrame) ([]any, error) {
 	// Every response frame includes a status byte that we can use to construct a GetStatusResponse. Even in the case
 	// where GetStatus is explicitly requested, this results in an empty CSAFE data payload and just the status byte.
 	// For this reason, we'll always create _at least_ a GetStatusResponse event for every response frame.
@@ -39,25 +106,52 @@ func parseResponses(f csafe.ExtendedResponseFrame) ([]any, error) {
 
 	// Now parse out any additional command responses included in the frame.
 	for _, resp := range f.CommandResponses {
-		r, err := unwrap(resp)
-		if err != nil {
-			slog.Error("failed to unwrap response", slog.Any("error", err))
+		if !isWrapper(resp.Command) {
+			parsed, ok, err := parseResponse(log, parserMap, resp)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				responses = append(responses, parsed)
+			}
 			continue
 		}
 
-		parser, ok := parserMap[r.Command]
-		if !ok {
-			slog.Warn("unsupported command response", slog.String("command", hex.EncodeToString([]byte{resp.Command})))
+		nested, err := unwrap(resp)
+		if err != nil {
+			log.Error("failed to unwrap response", slog.Any("error", err))
 			continue
 		}
 
-		parsedResp, err := parser(r.Data)
-		if err != nil {
-			return nil, err
+		for _, r := range nested {
+			parsed, ok, err := parseResponse(log, pmParserMap, r)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				responses = append(responses, parsed)
+			}
 		}
-
-		responses = append(responses, parsedResp)
 	}
 
 	return responses, nil
 }
+
+// parseResponse parses a single command response with the matching parser. It reports false for responses without
+// a parser, such as the empty acknowledgements of commands that return no data.
+func parseResponse(log *slog.Logger, parsers map[byte]parserFunc, r csafe.Response) (any, bool, error) {
+	parser, ok := parsers[r.Command]
+	if !ok {
+		if len(r.Data) > 0 {
+			log.Warn("unsupported command response", slog.String("command", hex.EncodeToString([]byte{r.Command})))
+		}
+		return nil, false, nil
+	}
+
+	parsedResp, err := parser(r.Data)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return parsedResp, true, nil
+}
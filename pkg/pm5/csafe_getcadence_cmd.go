@@ -0,0 +1,29 @@
+package pm5
+
+import (
+	"encoding/binary"
+
+	"github.com/seagrayinc/gorow/internal/csafe"
+)
+
+const csafe_GETCADENCE_CMD = 0xA7
+
+func GetCadence() Command {
+	return csafe.ShortCommand(csafe_GETCADENCE_CMD)
+}
+
+type GetCadenceResponse struct {
+	StrokesPerMinute int
+	UnitsSpecifier   byte
+}
+
+func parseGetCadenceResponse(b []byte) (GetCadenceResponse, error) {
+	if err := checkLength(b, 3); err != nil {
+		return GetCadenceResponse{}, err
+	}
+
+	return GetCadenceResponse{
+		StrokesPerMinute: int(binary.LittleEndian.Uint16(b[:2])),
+		UnitsSpecifier:   b[2],
+	}, nil
+}
@@ -0,0 +1,25 @@
+package pm5
+
+import (
+	"encoding/binary"
+
+	"github.com/seagrayinc/gorow/internal/csafe"
+)
+
+const csafe_GETCALORIES_CMD = 0xA3
+
+func GetCalories() Command {
+	return csafe.ShortCommand(csafe_GETCALORIES_CMD)
+}
+
+type GetCaloriesResponse struct {
+	Calories int // Total calories burned
+}
+
+func parseGetCaloriesResponse(b []byte) (GetCaloriesResponse, error) {
+	if err := checkLength(b, 2); err != nil {
+		return GetCaloriesResponse{}, err
+	}
+
+	return GetCaloriesResponse{Calories: int(binary.LittleEndian.Uint16(b[:2]))}, nil
+}
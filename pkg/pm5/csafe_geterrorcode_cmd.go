@@ -17,6 +17,10 @@ type GetErrorCodeResponse struct {
 }
 
 func parseGetErrorCodeResponse(b []byte) (GetErrorCodeResponse, error) {
+	if err := checkLength(b, 3); err != nil {
+		return GetErrorCodeResponse{}, err
+	}
+
 	// Byte 0: Error Code (LSB)
 	// Byte 1: Error Code
 	// Byte 2: Error Code (MSB)
@@ -0,0 +1,37 @@
+package pm5
+
+import (
+	"encoding/binary"
+
+	"github.com/seagrayinc/gorow/internal/csafe"
+)
+
+const csafe_GETHORIZONTAL_CMD = 0xA1
+
+func GetHorizontal() Command {
+	return csafe.ShortCommand(csafe_GETHORIZONTAL_CMD)
+}
+
+type GetHorizontalResponse struct {
+	Distance       int // Distance in the units given by UnitsSpecifier
+	UnitsSpecifier byte
+	Meters         float64 // Distance converted to meters
+}
+
+func parseGetHorizontalResponse(b []byte) (GetHorizontalResponse, error) {
+	if err := checkLength(b, 3); err != nil {
+		return GetHorizontalResponse{}, err
+	}
+
+	distance := int(binary.LittleEndian.Uint16(b[:2]))
+	meters, err := distanceMeters(distance, b[2])
+	if err != nil {
+		return GetHorizontalResponse{}, err
+	}
+
+	return GetHorizontalResponse{
+		Distance:       distance,
+		UnitsSpecifier: b[2],
+		Meters:         meters,
+	}, nil
+}
@@ -0,0 +1,29 @@
+package pm5
+
+import (
+	"github.com/seagrayinc/gorow/internal/csafe"
+)
+
+const csafe_GETHRCUR_CMD = 0xB0
+
+func GetHRCur() Command {
+	return csafe.ShortCommand(csafe_GETHRCUR_CMD)
+}
+
+type GetHRCurResponse struct {
+	BeatsPerMinute int
+}
+
+// Connected reports whether the PM is receiving data from a heart rate belt. Without one it reports 0 or 255 beats
+// per minute.
+func (r GetHRCurResponse) Connected() bool {
+	return r.BeatsPerMinute != 0 && r.BeatsPerMinute != 255
+}
+
+func parseGetHRCurResponse(b []byte) (GetHRCurResponse, error) {
+	if err := checkLength(b, 1); err != nil {
+		return GetHRCurResponse{}, err
+	}
+
+	return GetHRCurResponse{BeatsPerMinute: int(b[0])}, nil
+}
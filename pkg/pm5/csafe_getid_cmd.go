@@ -19,6 +19,10 @@ type GetIDResponse struct {
 }
 
 func parseGetIDResponse(b []byte) (GetIDResponse, error) {
+	if err := checkLength(b, 5); err != nil {
+		return GetIDResponse{}, err
+	}
+
 	return GetIDResponse{
 		ASCIIDigit0: b[0],
 		ASCIIDigit1: b[1],
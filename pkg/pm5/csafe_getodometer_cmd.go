@@ -18,6 +18,10 @@ type GetOdometerResponse struct {
 }
 
 func parseGetOdometerResponse(b []byte) (GetOdometerResponse, error) {
+	if err := checkLength(b, 5); err != nil {
+		return GetOdometerResponse{}, err
+	}
+
 	return GetOdometerResponse{
 		Distance:       binary.LittleEndian.Uint32(b[:4]),
 		UnitsSpecifier: b[4],
@@ -0,0 +1,38 @@
+package pm5
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/seagrayinc/gorow/internal/csafe"
+)
+
+const csafe_GETPACE_CMD = 0xA6
+
+func GetPace() Command {
+	return csafe.ShortCommand(csafe_GETPACE_CMD)
+}
+
+type GetPaceResponse struct {
+	Pace           int // Pace in the units given by UnitsSpecifier
+	UnitsSpecifier byte
+	Per500m        time.Duration // Pace converted to the time per 500 m
+}
+
+func parseGetPaceResponse(b []byte) (GetPaceResponse, error) {
+	if err := checkLength(b, 3); err != nil {
+		return GetPaceResponse{}, err
+	}
+
+	pace := int(binary.LittleEndian.Uint16(b[:2]))
+	per500m, err := pacePer500m(pace, b[2])
+	if err != nil {
+		return GetPaceResponse{}, err
+	}
+
+	return GetPaceResponse{
+		Pace:           pace,
+		UnitsSpecifier: b[2],
+		Per500m:        per500m,
+	}, nil
+}
@@ -0,0 +1,8 @@
+package pm5
+
+// csafe_GETPMCFG_CMD wraps the PM-specific configuration get commands (CSAFE_PM_GET_WORKOUTSTATE and friends).
+const csafe_GETPMCFG_CMD = 0x7E
+
+func getPMCfg(commands ...Command) Command {
+	return wrap(csafe_GETPMCFG_CMD, commands...)
+}
@@ -0,0 +1,8 @@
+package pm5
+
+// csafe_GETPMDATA_CMD wraps the PM-specific data get commands (CSAFE_PM_GET_STROKESTATS and friends).
+const csafe_GETPMDATA_CMD = 0x7F
+
+func getPMData(commands ...Command) Command {
+	return wrap(csafe_GETPMDATA_CMD, commands...)
+}
@@ -22,6 +22,10 @@ type GetPowerResponse struct {
 }
 
 func parseGetPowerResponse(b []byte) (GetPowerResponse, error) {
+	if err := checkLength(b, 3); err != nil {
+		return GetPowerResponse{}, err
+	}
+
 	return GetPowerResponse{
 		StrokeWatts:    int(binary.LittleEndian.Uint16(b[:2])),
 		UnitsSpecifier: b[2],
@@ -15,6 +15,10 @@ type GetSerialResponse struct {
 }
 
 func parseGetSerialResponse(b []byte) (GetSerialResponse, error) {
+	if err := checkLength(b, 9); err != nil {
+		return GetSerialResponse{}, err
+	}
+
 	return GetSerialResponse{
 		SerialNumber: string(b[:9]),
 	}, nil
@@ -0,0 +1,27 @@
+package pm5
+
+import (
+	"time"
+
+	"github.com/seagrayinc/gorow/internal/csafe"
+)
+
+const csafe_GETTWORK_CMD = 0xA0
+
+func GetTWork() Command {
+	return csafe.ShortCommand(csafe_GETTWORK_CMD)
+}
+
+type GetTWorkResponse struct {
+	WorkTime time.Duration // Elapsed work time, with one second resolution
+}
+
+func parseGetTWorkResponse(b []byte) (GetTWorkResponse, error) {
+	if err := checkLength(b, 3); err != nil {
+		return GetTWorkResponse{}, err
+	}
+
+	return GetTWorkResponse{
+		WorkTime: time.Duration(b[0])*time.Hour + time.Duration(b[1])*time.Minute + time.Duration(b[2])*time.Second,
+	}, nil
+}
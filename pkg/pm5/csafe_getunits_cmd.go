@@ -15,6 +15,10 @@ type GetUnitsResponse struct {
 }
 
 func parseGetUnitsResponse(b []byte) (GetUnitsResponse, error) {
+	if err := checkLength(b, 1); err != nil {
+		return GetUnitsResponse{}, err
+	}
+
 	return GetUnitsResponse{
 		UnitsType: b[0],
 	}, nil
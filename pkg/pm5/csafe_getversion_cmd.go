@@ -21,6 +21,10 @@ type GetVersionResponse struct {
 }
 
 func parseGetVersionResponse(b []byte) (GetVersionResponse, error) {
+	if err := checkLength(b, 7); err != nil {
+		return GetVersionResponse{}, err
+	}
+
 	return GetVersionResponse{
 		ManufacturerID:  int(b[0]),
 		ClassID:         int(b[1]),
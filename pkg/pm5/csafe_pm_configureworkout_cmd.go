@@ -0,0 +1,21 @@
+package pm5
+
+import (
+	"github.com/seagrayinc/gorow/internal/csafe"
+)
+
+const csafe_PM_CONFIGURE_WORKOUT = 0x14
+
+// ConfigureWorkout enables or disables workout programming mode. It completes each interval of a variable interval
+// workout.
+func ConfigureWorkout(enable bool) Command {
+	return setPMCfg(configureWorkout(enable))
+}
+
+func configureWorkout(enable bool) Command {
+	var mode byte
+	if enable {
+		mode = 1
+	}
+	return csafe.LongCommand(csafe_PM_CONFIGURE_WORKOUT, []byte{mode})
+}
@@ -0,0 +1,27 @@
+package pm5
+
+import (
+	"github.com/seagrayinc/gorow/internal/csafe"
+)
+
+const csafe_PM_GET_AVGHEARTRATE = 0xB6
+
+func GetAvgHeartRate() Command {
+	return getPMData(csafe.ShortCommand(csafe_PM_GET_AVGHEARTRATE))
+}
+
+type GetAvgHeartRateResponse struct {
+	BeatsPerMinute int // Average heart rate of the split or interval, zero without a belt
+}
+
+func parseGetAvgHeartRateResponse(b []byte) (GetAvgHeartRateResponse, error) {
+	if err := checkLength(b, 1); err != nil {
+		return GetAvgHeartRateResponse{}, err
+	}
+
+	bpm := int(b[0])
+	if bpm == 255 {
+		bpm = 0
+	}
+	return GetAvgHeartRateResponse{BeatsPerMinute: bpm}, nil
+}
@@ -0,0 +1,23 @@
+package pm5
+
+import (
+	"github.com/seagrayinc/gorow/internal/csafe"
+)
+
+const csafe_PM_GET_DRAGFACTOR = 0xC1
+
+func GetDragFactor() Command {
+	return getPMData(csafe.ShortCommand(csafe_PM_GET_DRAGFACTOR))
+}
+
+type GetDragFactorResponse struct {
+	DragFactor int
+}
+
+func parseGetDragFactorResponse(b []byte) (GetDragFactorResponse, error) {
+	if err := checkLength(b, 1); err != nil {
+		return GetDragFactorResponse{}, err
+	}
+
+	return GetDragFactorResponse{DragFactor: int(b[0])}, nil
+}
@@ -0,0 +1,28 @@
+package pm5
+
+import (
+	"github.com/seagrayinc/gorow/internal/csafe"
+)
+
+const csafe_PM_GET_FORCEPLOTDATA = 0x6B
+
+// GetForcePlotData reads the next block of force curve samples of the current stroke. The PM buffers the samples
+// of the drive in progress and returns up to 16 of them per request, so read repeatedly until a response carries no
+// samples.
+func GetForcePlotData() Command {
+	return getPMData(csafe.LongCommand(csafe_PM_GET_FORCEPLOTDATA, []byte{blockLength}))
+}
+
+type GetForcePlotDataResponse struct {
+	BytesRead int
+	Samples   []int // Force in lbs
+}
+
+func parseGetForcePlotDataResponse(b []byte) (GetForcePlotDataResponse, error) {
+	n, samples, err := parseBlock(b)
+	if err != nil {
+		return GetForcePlotDataResponse{}, err
+	}
+
+	return GetForcePlotDataResponse{BytesRead: n, Samples: samples}, nil
+}
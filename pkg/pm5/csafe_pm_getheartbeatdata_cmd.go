@@ -0,0 +1,26 @@
+package pm5
+
+import (
+	"github.com/seagrayinc/gorow/internal/csafe"
+)
+
+const csafe_PM_GET_HEARTBEATDATA = 0x6C
+
+// GetHeartbeatData reads the next block of heartbeat data buffered by the PM, up to 16 beats per request.
+func GetHeartbeatData() Command {
+	return getPMData(csafe.LongCommand(csafe_PM_GET_HEARTBEATDATA, []byte{blockLength}))
+}
+
+type GetHeartbeatDataResponse struct {
+	BytesRead int
+	BeatTimes []int // Beat event times in 1/1024 s, wrapping at 65536 as sent by ANT+ belts
+}
+
+func parseGetHeartbeatDataResponse(b []byte) (GetHeartbeatDataResponse, error) {
+	n, beats, err := parseBlock(b)
+	if err != nil {
+		return GetHeartbeatDataResponse{}, err
+	}
+
+	return GetHeartbeatDataResponse{BytesRead: n, BeatTimes: beats}, nil
+}
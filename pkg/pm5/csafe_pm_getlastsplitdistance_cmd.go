@@ -0,0 +1,25 @@
+package pm5
+
+import (
+	"encoding/binary"
+
+	"github.com/seagrayinc/gorow/internal/csafe"
+)
+
+const csafe_PM_GET_LASTSPLITDISTANCE = 0xBC
+
+func GetLastSplitDistance() Command {
+	return getPMData(csafe.ShortCommand(csafe_PM_GET_LASTSPLITDISTANCE))
+}
+
+type GetLastSplitDistanceResponse struct {
+	Meters int // Distance of the last split or interval
+}
+
+func parseGetLastSplitDistanceResponse(b []byte) (GetLastSplitDistanceResponse, error) {
+	if err := checkLength(b, 4); err != nil {
+		return GetLastSplitDistanceResponse{}, err
+	}
+
+	return GetLastSplitDistanceResponse{Meters: int(binary.LittleEndian.Uint32(b[:4]))}, nil
+}
@@ -0,0 +1,28 @@
+package pm5
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/seagrayinc/gorow/internal/csafe"
+)
+
+const csafe_PM_GET_LASTSPLITTIME = 0xBA
+
+func GetLastSplitTime() Command {
+	return getPMData(csafe.ShortCommand(csafe_PM_GET_LASTSPLITTIME))
+}
+
+type GetLastSplitTimeResponse struct {
+	SplitTime time.Duration // Time of the last split or interval, with 0.01 s resolution
+}
+
+func parseGetLastSplitTimeResponse(b []byte) (GetLastSplitTimeResponse, error) {
+	if err := checkLength(b, 4); err != nil {
+		return GetLastSplitTimeResponse{}, err
+	}
+
+	return GetLastSplitTimeResponse{
+		SplitTime: time.Duration(binary.LittleEndian.Uint32(b[:4])) * 10 * time.Millisecond,
+	}, nil
+}
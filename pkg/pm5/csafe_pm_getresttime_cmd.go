@@ -0,0 +1,26 @@
+package pm5
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/seagrayinc/gorow/internal/csafe"
+)
+
+const csafe_PM_GET_RESTTIME = 0xCF
+
+func GetRestTime() Command {
+	return getPMData(csafe.ShortCommand(csafe_PM_GET_RESTTIME))
+}
+
+type GetRestTimeResponse struct {
+	RestTime time.Duration // Rest time of the interval, with one second resolution
+}
+
+func parseGetRestTimeResponse(b []byte) (GetRestTimeResponse, error) {
+	if err := checkLength(b, 2); err != nil {
+		return GetRestTimeResponse{}, err
+	}
+
+	return GetRestTimeResponse{RestTime: time.Duration(binary.LittleEndian.Uint16(b[:2])) * time.Second}, nil
+}
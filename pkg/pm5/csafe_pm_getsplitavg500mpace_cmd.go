@@ -0,0 +1,28 @@
+package pm5
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/seagrayinc/gorow/internal/csafe"
+)
+
+const csafe_PM_GET_SPLITAVG500MPACE = 0xAB
+
+func GetSplitAvg500mPace() Command {
+	return getPMData(csafe.ShortCommand(csafe_PM_GET_SPLITAVG500MPACE))
+}
+
+type GetSplitAvg500mPaceResponse struct {
+	Per500m time.Duration // Average pace of the split or interval, with 0.01 s resolution
+}
+
+func parseGetSplitAvg500mPaceResponse(b []byte) (GetSplitAvg500mPaceResponse, error) {
+	if err := checkLength(b, 4); err != nil {
+		return GetSplitAvg500mPaceResponse{}, err
+	}
+
+	return GetSplitAvg500mPaceResponse{
+		Per500m: time.Duration(binary.LittleEndian.Uint32(b[:4])) * 10 * time.Millisecond,
+	}, nil
+}
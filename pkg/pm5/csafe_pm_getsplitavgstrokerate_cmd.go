@@ -0,0 +1,23 @@
+package pm5
+
+import (
+	"github.com/seagrayinc/gorow/internal/csafe"
+)
+
+const csafe_PM_GET_SPLITAVGSTROKERATE = 0xB4
+
+func GetSplitAvgStrokeRate() Command {
+	return getPMData(csafe.ShortCommand(csafe_PM_GET_SPLITAVGSTROKERATE))
+}
+
+type GetSplitAvgStrokeRateResponse struct {
+	StrokesPerMinute int // Average stroke rate of the split or interval
+}
+
+func parseGetSplitAvgStrokeRateResponse(b []byte) (GetSplitAvgStrokeRateResponse, error) {
+	if err := checkLength(b, 1); err != nil {
+		return GetSplitAvgStrokeRateResponse{}, err
+	}
+
+	return GetSplitAvgStrokeRateResponse{StrokesPerMinute: int(b[0])}, nil
+}
@@ -0,0 +1,28 @@
+package pm5
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/seagrayinc/gorow/internal/csafe"
+)
+
+const csafe_PM_GET_STROKE500MPACE = 0xA8
+
+func GetStroke500mPace() Command {
+	return getPMData(csafe.ShortCommand(csafe_PM_GET_STROKE500MPACE))
+}
+
+type GetStroke500mPaceResponse struct {
+	Per500m time.Duration // Pace of the last stroke, with 0.01 s resolution
+}
+
+func parseGetStroke500mPaceResponse(b []byte) (GetStroke500mPaceResponse, error) {
+	if err := checkLength(b, 4); err != nil {
+		return GetStroke500mPaceResponse{}, err
+	}
+
+	return GetStroke500mPaceResponse{
+		Per500m: time.Duration(binary.LittleEndian.Uint32(b[:4])) * 10 * time.Millisecond,
+	}, nil
+}
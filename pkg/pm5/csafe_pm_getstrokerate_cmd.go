@@ -0,0 +1,23 @@
+package pm5
+
+import (
+	"github.com/seagrayinc/gorow/internal/csafe"
+)
+
+const csafe_PM_GET_STROKERATE = 0xB3
+
+func GetStrokeRate() Command {
+	return getPMData(csafe.ShortCommand(csafe_PM_GET_STROKERATE))
+}
+
+type GetStrokeRateResponse struct {
+	StrokesPerMinute int
+}
+
+func parseGetStrokeRateResponse(b []byte) (GetStrokeRateResponse, error) {
+	if err := checkLength(b, 1); err != nil {
+		return GetStrokeRateResponse{}, err
+	}
+
+	return GetStrokeRateResponse{StrokesPerMinute: int(b[0])}, nil
+}
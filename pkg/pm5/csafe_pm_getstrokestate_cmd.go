@@ -7,14 +7,27 @@ import (
 const csafe_PM_GET_STROKESTATE = 0xBF
 
 func GetStrokeState() Command {
-	return wrap(csafe.ShortCommand(csafe_PM_GET_STROKESTATE))
+	return getPMData(csafe.ShortCommand(csafe_PM_GET_STROKESTATE))
 }
 
+// Stroke states reported in GetStrokeStateResponse.
+const (
+	StrokeStateWaitingForWheelToReachMinSpeed = 0
+	StrokeStateWaitingForWheelToAccelerate    = 1
+	StrokeStateDriving                        = 2
+	StrokeStateDwellingAfterDrive             = 3
+	StrokeStateRecovery                       = 4
+)
+
 type GetStrokeStateResponse struct {
 	StrokeState int
 }
 
 func parseGetStrokeStateResponse(b []byte) (GetStrokeStateResponse, error) {
+	if err := checkLength(b, 1); err != nil {
+		return GetStrokeStateResponse{}, err
+	}
+
 	//Stroke State
 	//typedef enum {
 	//	STROKESTATE_WAITING_FOR_WHEEL_TO_REACH_MIN_SPEED_STATE, /**< FW to reach min speed state (0). */
@@ -9,7 +9,7 @@ import (
 const csafe_PM_GET_STROKESTATS = 0x6E
 
 func GetStrokeStats() Command {
-	return wrap(csafe.LongCommand(csafe_PM_GET_STROKESTATS, []byte{0}))
+	return getPMData(csafe.LongCommand(csafe_PM_GET_STROKESTATS, []byte{0}))
 }
 
 type GetStrokeStatsResponse struct {
@@ -25,6 +25,10 @@ type GetStrokeStatsResponse struct {
 }
 
 func parseGetStrokeStatsResponse(b []byte) (GetStrokeStatsResponse, error) {
+	if err := checkLength(b, 16); err != nil {
+		return GetStrokeStatsResponse{}, err
+	}
+
 	return GetStrokeStatsResponse{
 		StrokeDistance:     int(binary.LittleEndian.Uint16(b[0:2])),
 		StrokeDriveTime:    int(b[2]),
@@ -0,0 +1,29 @@
+package pm5
+
+import (
+	"encoding/binary"
+
+	"github.com/seagrayinc/gorow/internal/csafe"
+)
+
+const csafe_PM_GET_WORKDISTANCE = 0xA3
+
+func GetWorkDistance() Command {
+	return getPMData(csafe.ShortCommand(csafe_PM_GET_WORKDISTANCE))
+}
+
+type GetWorkDistanceResponse struct {
+	Meters   float64 // Work distance, with 0.1 m resolution
+	Fraction byte    // Fractional work distance as reported by the PM, not included in Meters
+}
+
+func parseGetWorkDistanceResponse(b []byte) (GetWorkDistanceResponse, error) {
+	if err := checkLength(b, 5); err != nil {
+		return GetWorkDistanceResponse{}, err
+	}
+
+	return GetWorkDistanceResponse{
+		Meters:   float64(binary.LittleEndian.Uint32(b[:4])) / 10,
+		Fraction: b[4],
+	}, nil
+}
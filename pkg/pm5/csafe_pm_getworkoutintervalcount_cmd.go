@@ -0,0 +1,23 @@
+package pm5
+
+import (
+	"github.com/seagrayinc/gorow/internal/csafe"
+)
+
+const csafe_PM_GET_WORKOUTINTERVALCOUNT = 0x9F
+
+func GetWorkoutIntervalCount() Command {
+	return getPMCfg(csafe.ShortCommand(csafe_PM_GET_WORKOUTINTERVALCOUNT))
+}
+
+type GetWorkoutIntervalCountResponse struct {
+	IntervalCount int
+}
+
+func parseGetWorkoutIntervalCountResponse(b []byte) (GetWorkoutIntervalCountResponse, error) {
+	if err := checkLength(b, 1); err != nil {
+		return GetWorkoutIntervalCountResponse{}, err
+	}
+
+	return GetWorkoutIntervalCountResponse{IntervalCount: int(b[0])}, nil
+}
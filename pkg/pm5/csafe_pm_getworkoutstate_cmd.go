@@ -7,7 +7,7 @@ import (
 const csafe_PM_GET_WORKOUTSTATE = 0x8D
 
 func GetWorkoutState() Command {
-	return wrap(csafe.ShortCommand(csafe_PM_GET_WORKOUTSTATE))
+	return getPMCfg(csafe.ShortCommand(csafe_PM_GET_WORKOUTSTATE))
 }
 
 type GetWorkoutStateResponse struct {
@@ -33,6 +33,10 @@ var WorkoutStateMap = map[int]string{
 }
 
 func parseGetWorkoutStateResponse(b []byte) (GetWorkoutStateResponse, error) {
+	if err := checkLength(b, 1); err != nil {
+		return GetWorkoutStateResponse{}, err
+	}
+
 	//typedef enum {
 	//	WORKOUTSTATE_WAITTOBEGIN, /**< Wait to begin state (0). */
 	//	WORKOUTSTATE_WORKOUTROW, /**< Workout row state (1). */
@@ -0,0 +1,23 @@
+package pm5
+
+import (
+	"github.com/seagrayinc/gorow/internal/csafe"
+)
+
+const csafe_PM_GET_WORKOUTTYPE = 0x89
+
+func GetWorkoutType() Command {
+	return getPMCfg(csafe.ShortCommand(csafe_PM_GET_WORKOUTTYPE))
+}
+
+type GetWorkoutTypeResponse struct {
+	WorkoutType WorkoutType
+}
+
+func parseGetWorkoutTypeResponse(b []byte) (GetWorkoutTypeResponse, error) {
+	if err := checkLength(b, 1); err != nil {
+		return GetWorkoutTypeResponse{}, err
+	}
+
+	return GetWorkoutTypeResponse{WorkoutType: WorkoutType(b[0])}, nil
+}
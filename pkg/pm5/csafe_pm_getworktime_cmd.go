@@ -0,0 +1,30 @@
+package pm5
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/seagrayinc/gorow/internal/csafe"
+)
+
+const csafe_PM_GET_WORKTIME = 0xA0
+
+func GetWorkTime() Command {
+	return getPMData(csafe.ShortCommand(csafe_PM_GET_WORKTIME))
+}
+
+type GetWorkTimeResponse struct {
+	WorkTime time.Duration // Elapsed work time, with 0.01 s resolution
+	Fraction byte          // Fractional work time as reported by the PM, not included in WorkTime
+}
+
+func parseGetWorkTimeResponse(b []byte) (GetWorkTimeResponse, error) {
+	if err := checkLength(b, 5); err != nil {
+		return GetWorkTimeResponse{}, err
+	}
+
+	return GetWorkTimeResponse{
+		WorkTime: time.Duration(binary.LittleEndian.Uint32(b[:4])) * 10 * time.Millisecond,
+		Fraction: b[4],
+	}, nil
+}
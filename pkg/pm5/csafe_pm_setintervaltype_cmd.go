@@ -0,0 +1,46 @@
+package pm5
+
+import (
+	"github.com/seagrayinc/gorow/internal/csafe"
+)
+
+const csafe_PM_SET_INTERVALTYPE = 0x17
+
+// IntervalType selects the kind of the current interval of a variable interval workout.
+type IntervalType byte
+
+//	typedef enum {
+//		INTERVALTYPE_TIME, /**< Time interval type (0). */
+//		INTERVALTYPE_DIST, /**< Distance interval type (1). */
+//		INTERVALTYPE_REST, /**< Rest interval type (2). */
+//		INTERVALTYPE_TIMERESTUNDEFINED, /**< Time undefined rest interval type (3). */
+//		INTERVALTYPE_DISTANCERESTUNDEFINED, /**< Distance undefined rest interval type (4). */
+//		INTERVALTYPE_RESTUNDEFINED, /**< Undefined rest interval type (5). */
+//		INTERVALTYPE_CAL, /**< Calorie interval type (6). */
+//		INTERVALTYPE_CALRESTUNDEFINED, /**< Calorie undefined rest interval type (7). */
+//		INTERVALTYPE_WATTMINUTE, /**< Watt-minute interval type (8). */
+//		INTERVALTYPE_WATTMINUTERESTUNDEFINED, /**< Watt-minute undefined rest interval type (9). */
+//		INTERVALTYPE_NONE = 255 /**< No interval type (255). */
+//	} OBJ_INTERVALTYPE_T;
+const (
+	IntervalTypeTime                    IntervalType = 0
+	IntervalTypeDistance                IntervalType = 1
+	IntervalTypeRest                    IntervalType = 2
+	IntervalTypeTimeRestUndefined       IntervalType = 3
+	IntervalTypeDistanceRestUndefined   IntervalType = 4
+	IntervalTypeRestUndefined           IntervalType = 5
+	IntervalTypeCalorie                 IntervalType = 6
+	IntervalTypeCalorieRestUndefined    IntervalType = 7
+	IntervalTypeWattMinute              IntervalType = 8
+	IntervalTypeWattMinuteRestUndefined IntervalType = 9
+	IntervalTypeNone                    IntervalType = 255
+)
+
+// SetIntervalType sets the type of the current interval.
+func SetIntervalType(t IntervalType) Command {
+	return setPMCfg(setIntervalType(t))
+}
+
+func setIntervalType(t IntervalType) Command {
+	return csafe.LongCommand(csafe_PM_SET_INTERVALTYPE, []byte{byte(t)})
+}
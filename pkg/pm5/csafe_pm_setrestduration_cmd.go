@@ -0,0 +1,19 @@
+package pm5
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/seagrayinc/gorow/internal/csafe"
+)
+
+const csafe_PM_SET_RESTDURATION = 0x04
+
+// SetRestDuration sets the rest following the current interval. The PM counts rest in whole seconds.
+func SetRestDuration(d time.Duration) Command {
+	return setPMCfg(setRestDuration(d))
+}
+
+func setRestDuration(d time.Duration) Command {
+	return csafe.LongCommand(csafe_PM_SET_RESTDURATION, binary.BigEndian.AppendUint16(nil, uint16(d/time.Second)))
+}
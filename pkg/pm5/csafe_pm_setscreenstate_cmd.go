@@ -0,0 +1,41 @@
+package pm5
+
+import (
+	"github.com/seagrayinc/gorow/internal/csafe"
+)
+
+const csafe_PM_SET_SCREENSTATE = 0x13
+
+// ScreenType and ScreenValue select the screen action requested with SetScreenState.
+type (
+	ScreenType  byte
+	ScreenValue byte
+)
+
+const (
+	ScreenTypeNone    ScreenType = 0
+	ScreenTypeWorkout ScreenType = 1
+	ScreenTypeRace    ScreenType = 2
+	ScreenTypeCSAFE   ScreenType = 3
+	ScreenTypeDiag    ScreenType = 4
+	ScreenTypeMfg     ScreenType = 5
+)
+
+// Screen values for ScreenTypeWorkout.
+const (
+	ScreenValueWorkoutNone           ScreenValue = 0
+	ScreenValueWorkoutPrepareToRow   ScreenValue = 1
+	ScreenValueWorkoutTerminate      ScreenValue = 2
+	ScreenValueWorkoutRearm          ScreenValue = 3
+	ScreenValueWorkoutTerminateLogin ScreenValue = 4
+)
+
+// SetScreenState asks the PM to perform a screen action, e.g. to start the programmed workout with
+// SetScreenState(ScreenTypeWorkout, ScreenValueWorkoutPrepareToRow).
+func SetScreenState(t ScreenType, v ScreenValue) Command {
+	return setPMCfg(setScreenState(t, v))
+}
+
+func setScreenState(t ScreenType, v ScreenValue) Command {
+	return csafe.LongCommand(csafe_PM_SET_SCREENSTATE, []byte{byte(t), byte(v)})
+}
@@ -0,0 +1,17 @@
+package pm5
+
+import (
+	"github.com/seagrayinc/gorow/internal/csafe"
+)
+
+const csafe_PM_SET_SPLITDURATION = 0x05
+
+// SetSplitDuration sets the split length of a single-piece workout. It must have the same type as the workout
+// duration.
+func SetSplitDuration(d WorkoutDuration) Command {
+	return setPMCfg(setSplitDuration(d))
+}
+
+func setSplitDuration(d WorkoutDuration) Command {
+	return csafe.LongCommand(csafe_PM_SET_SPLITDURATION, d.bytes())
+}
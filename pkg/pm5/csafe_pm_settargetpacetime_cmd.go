@@ -0,0 +1,19 @@
+package pm5
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/seagrayinc/gorow/internal/csafe"
+)
+
+const csafe_PM_SET_TARGETPACETIME = 0x06
+
+// SetTargetPaceTime sets the pace per 500 m shown by the PM's pace boat, with 0.01 s resolution.
+func SetTargetPaceTime(pace time.Duration) Command {
+	return setPMCfg(setTargetPaceTime(pace))
+}
+
+func setTargetPaceTime(pace time.Duration) Command {
+	return csafe.LongCommand(csafe_PM_SET_TARGETPACETIME, binary.BigEndian.AppendUint32(nil, uint32(pace/(10*time.Millisecond))))
+}
@@ -0,0 +1,66 @@
+package pm5
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/seagrayinc/gorow/internal/csafe"
+)
+
+const csafe_PM_SET_WORKOUTDURATION = 0x03
+
+// DurationType is the unit of a WorkoutDuration.
+type DurationType byte
+
+const (
+	DurationTypeTime        DurationType = 0x00
+	DurationTypeCalories    DurationType = 0x40
+	DurationTypeDistance    DurationType = 0x80
+	DurationTypeWattMinutes DurationType = 0xC0
+)
+
+// WorkoutDuration is the length of a workout, interval or split. Value is in hundredths of a second for time,
+// meters for distance, and whole calories or watt-minutes otherwise.
+type WorkoutDuration struct {
+	Type  DurationType
+	Value uint32
+}
+
+// Time returns a time-based WorkoutDuration with 0.01 s resolution.
+func Time(d time.Duration) WorkoutDuration {
+	return WorkoutDuration{Type: DurationTypeTime, Value: uint32(d / (10 * time.Millisecond))}
+}
+
+// Meters returns a distance-based WorkoutDuration.
+func Meters(m int) WorkoutDuration {
+	return WorkoutDuration{Type: DurationTypeDistance, Value: uint32(m)}
+}
+
+// Calories returns a calorie-based WorkoutDuration.
+func Calories(cal int) WorkoutDuration {
+	return WorkoutDuration{Type: DurationTypeCalories, Value: uint32(cal)}
+}
+
+// WattMinutes returns a watt-minute-based WorkoutDuration.
+func WattMinutes(wm int) WorkoutDuration {
+	return WorkoutDuration{Type: DurationTypeWattMinutes, Value: uint32(wm)}
+}
+
+// IsZero reports whether d is the zero WorkoutDuration.
+func (d WorkoutDuration) IsZero() bool {
+	return d.Value == 0
+}
+
+// bytes encodes the duration as the type byte followed by the value, MSB first.
+func (d WorkoutDuration) bytes() []byte {
+	return binary.BigEndian.AppendUint32([]byte{byte(d.Type)}, d.Value)
+}
+
+// SetWorkoutDuration sets the length of a single-piece workout, or of the current interval.
+func SetWorkoutDuration(d WorkoutDuration) Command {
+	return setPMCfg(setWorkoutDuration(d))
+}
+
+func setWorkoutDuration(d WorkoutDuration) Command {
+	return csafe.LongCommand(csafe_PM_SET_WORKOUTDURATION, d.bytes())
+}
@@ -0,0 +1,16 @@
+package pm5
+
+import (
+	"github.com/seagrayinc/gorow/internal/csafe"
+)
+
+const csafe_PM_SET_WORKOUTINTERVALCOUNT = 0x18
+
+// SetWorkoutIntervalCount selects the zero-based interval that following interval commands configure.
+func SetWorkoutIntervalCount(n int) Command {
+	return setPMCfg(setWorkoutIntervalCount(n))
+}
+
+func setWorkoutIntervalCount(n int) Command {
+	return csafe.LongCommand(csafe_PM_SET_WORKOUTINTERVALCOUNT, []byte{byte(n)})
+}
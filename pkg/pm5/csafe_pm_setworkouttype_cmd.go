@@ -0,0 +1,50 @@
+package pm5
+
+import (
+	"github.com/seagrayinc/gorow/internal/csafe"
+)
+
+const csafe_PM_SET_WORKOUTTYPE = 0x01
+
+// WorkoutType selects the kind of workout programmed with SetWorkoutType.
+type WorkoutType byte
+
+//	typedef enum {
+//		WORKOUTTYPE_JUSTROW_NOSPLITS, /**< JustRow, no splits (0). */
+//		WORKOUTTYPE_JUSTROW_SPLITS, /**< JustRow, splits (1). */
+//		WORKOUTTYPE_FIXEDDIST_NOSPLITS, /**< Fixed distance, no splits (2). */
+//		WORKOUTTYPE_FIXEDDIST_SPLITS, /**< Fixed distance, splits (3). */
+//		WORKOUTTYPE_FIXEDTIME_NOSPLITS, /**< Fixed time, no splits (4). */
+//		WORKOUTTYPE_FIXEDTIME_SPLITS, /**< Fixed time, splits (5). */
+//		WORKOUTTYPE_FIXEDTIME_INTERVAL, /**< Fixed time interval (6). */
+//		WORKOUTTYPE_FIXEDDIST_INTERVAL, /**< Fixed distance interval (7). */
+//		WORKOUTTYPE_VARIABLE_INTERVAL, /**< Variable interval (8). */
+//		WORKOUTTYPE_VARIABLE_UNDEFINEDREST_INTERVAL, /**< Variable interval, undefined rest (9). */
+//		WORKOUTTYPE_FIXED_CALORIE, /**< Fixed calorie, splits (10). */
+//		WORKOUTTYPE_FIXED_WATTMINUTES, /**< Fixed watt-minutes, splits (11). */
+//		WORKOUTTYPE_FIXEDCALS_INTERVAL, /**< Fixed calorie interval (12). */
+//	} OBJ_WORKOUTTYPE_T;
+const (
+	WorkoutTypeJustRowNoSplits               WorkoutType = 0
+	WorkoutTypeJustRowSplits                 WorkoutType = 1
+	WorkoutTypeFixedDistanceNoSplits         WorkoutType = 2
+	WorkoutTypeFixedDistanceSplits           WorkoutType = 3
+	WorkoutTypeFixedTimeNoSplits             WorkoutType = 4
+	WorkoutTypeFixedTimeSplits               WorkoutType = 5
+	WorkoutTypeFixedTimeInterval             WorkoutType = 6
+	WorkoutTypeFixedDistanceInterval         WorkoutType = 7
+	WorkoutTypeVariableInterval              WorkoutType = 8
+	WorkoutTypeVariableUndefinedRestInterval WorkoutType = 9
+	WorkoutTypeFixedCalorieSplits            WorkoutType = 10
+	WorkoutTypeFixedWattMinuteSplits         WorkoutType = 11
+	WorkoutTypeFixedCalorieInterval          WorkoutType = 12
+)
+
+// SetWorkoutType selects the type of the workout being programmed.
+func SetWorkoutType(t WorkoutType) Command {
+	return setPMCfg(setWorkoutType(t))
+}
+
+func setWorkoutType(t WorkoutType) Command {
+	return csafe.LongCommand(csafe_PM_SET_WORKOUTTYPE, []byte{byte(t)})
+}
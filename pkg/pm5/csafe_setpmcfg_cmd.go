@@ -0,0 +1,8 @@
+package pm5
+
+// csafe_SETPMCFG_CMD wraps the PM-specific configuration set commands (CSAFE_PM_SET_WORKOUTTYPE and friends).
+const csafe_SETPMCFG_CMD = 0x76
+
+func setPMCfg(commands ...Command) Command {
+	return wrap(csafe_SETPMCFG_CMD, commands...)
+}
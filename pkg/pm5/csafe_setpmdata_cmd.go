@@ -0,0 +1,8 @@
+package pm5
+
+// csafe_SETPMDATA_CMD wraps the PM-specific data set commands (CSAFE_PM_SET_TEAM_DISTANCE and friends).
+const csafe_SETPMDATA_CMD = 0x77
+
+func setPMData(commands ...Command) Command {
+	return wrap(csafe_SETPMDATA_CMD, commands...)
+}
@@ -1,26 +1,5 @@
 package pm5
 
-import (
-	"errors"
-
-	"github.com/seagrayinc/gorow/internal/csafe"
-)
-
+// csafe_SETUSERCFG1_CMD is the legacy wrapper for PM-specific commands. The PM accepts any PM-specific command inside
+// it, and responses to commands sent this way come back inside it too.
 const csafe_SETUSERCFG1_CMD = 0x1A
-
-func wrap(c csafe.Command) csafe.Command {
-	return csafe.LongCommand(csafe_SETUSERCFG1_CMD, c)
-}
-
-func unwrap(r csafe.Response) (csafe.Response, error) {
-	if r.Command != csafe_SETUSERCFG1_CMD {
-		return r, nil
-	}
-
-	responses := csafe.ParseResponses(r.Data)
-	if len(responses) < 1 {
-		return csafe.Response{}, errors.New("malformed response")
-	}
-
-	return responses[0], nil
-}
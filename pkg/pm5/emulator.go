@@ -0,0 +1,604 @@
+package pm5
+
+import (
+	"context"
+	"encoding/binary"
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/seagrayinc/gorow/internal/csafe"
+)
+
+// Identity reported by an Emulator.
+const (
+	emulatorManufacturerID  = 22 // Concept2
+	emulatorClassID         = 2
+	emulatorModel           = 5
+	emulatorHardwareVersion = 500
+	emulatorFirmwareVersion = 171
+	emulatorSerialNumber    = "430000000"
+	emulatorUserID          = "00000"
+	unitsMetric             = 0x00
+	cadenceUnitsPerMinute   = 0x54
+)
+
+// emulatorTick is the resolution of the simulation, matching the 0.01 s resolution of the PM's timers.
+const emulatorTick = 10 * time.Millisecond
+
+// EmulatorConfig configures an Emulator. The zero value rows a Just Row workout at 2:00/500m and 24 strokes per minute
+// in real time.
+type EmulatorConfig struct {
+	// Rower rows the workouts. The default is NewSteadyRower(2*time.Minute, 24).
+	Rower Rower
+	// Workout is armed when the emulator starts, as if programmed with ProgramWorkout. The zero Workout is Just Row.
+	Workout Workout
+	// StartAfter is the simulated time the rower waits before starting an armed workout.
+	StartAfter time.Duration
+	// FixedIntervals is the number of intervals rowed in a fixed interval workout before the rower stops (default 4).
+	FixedIntervals int
+	// HeartRate is the heart rate reported by the emulated belt; zero emulates no belt.
+	HeartRate int
+	// Speed multiplies the passage of time in the simulation (default 1). With Manual set, the simulation only
+	// advances with Advance.
+	Speed  float64
+	Manual bool
+	// SerialNumber is the 9 digit serial number of the PM (default 430000000).
+	SerialNumber string
+	Logger       *slog.Logger
+}
+
+// Emulator is a virtual PM5 implementing Device, for integration tests and demos without an erg. It decodes the
+// CSAFE frames written to it with the same code that decodes the PM's responses, keeps the CSAFE machine state and
+// the frame toggle and status bits, and answers every command of this package from a simulated workout.
+//
+// Frames are processed in order. A frame holding an unsupported command, or a state change the machine state does
+// not allow, is rejected from that command on; the responses to the commands before it are still returned. Frames
+// reported bad or not ready can be scripted with FailFrames. Splits of single-piece workouts are not emulated: the
+// split commands report the whole piece.
+type Emulator struct {
+	cfg EmulatorConfig
+	log *slog.Logger
+
+	reports   chan Report
+	done      chan struct{}
+	closeOnce sync.Once
+
+	mu           sync.Mutex
+	lastSync     time.Time
+	clock        time.Duration // Simulated time since the emulator started
+	toggle       byte
+	machineState byte
+	odometer     float64
+
+	failStatus byte // Status of the frames failed with FailFrames
+	failFrames int  // Number of frames still to fail
+
+	program  Workout // Being programmed with the PM-specific set commands
+	selected int     // Interval being programmed, selected with CSAFE_PM_SET_WORKOUTINTERVALCOUNT
+	workout  *emulatedWorkout
+
+	heartbeats []int
+	nextBeat   time.Duration
+}
+
+// NewEmulator returns an Emulator with the configured workout armed.
+func NewEmulator(cfg EmulatorConfig) *Emulator {
+	if cfg.Rower == nil {
+		cfg.Rower = NewSteadyRower(2*time.Minute, 24)
+	}
+	if cfg.FixedIntervals == 0 {
+		cfg.FixedIntervals = 4
+	}
+	if cfg.Speed == 0 {
+		cfg.Speed = 1
+	}
+	if cfg.SerialNumber == "" {
+		cfg.SerialNumber = emulatorSerialNumber
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+
+	e := &Emulator{
+		cfg:          cfg,
+		log:          cfg.Logger,
+		reports:      make(chan Report),
+		done:         make(chan struct{}),
+		lastSync:     time.Now(),
+		machineState: MachineStateReady,
+		program:      cfg.Workout,
+	}
+	e.arm(cfg.Workout)
+	return e
+}
+
+func (e *Emulator) Close() error {
+	return nil
+}
+
+// WriteReport processes the frames in the report and sends the response to each through PollReports. It blocks until
+// the responses are polled.
+func (e *Emulator) WriteReport(ctx context.Context, r Report) error {
+	var replies []Report
+	for _, frame := range e.respond(r) {
+		replies = append(replies, e.report(r.ID, frame))
+	}
+
+	for _, reply := range replies {
+		select {
+		case e.reports <- reply:
+		case <-e.done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func (e *Emulator) PollReports(ctx context.Context) <-chan Report {
+	out := make(chan Report)
+
+	go func() {
+		defer close(out)
+		defer e.closeOnce.Do(func() { close(e.done) })
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case r := <-e.reports:
+				select {
+				case out <- r:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+// Advance moves the simulation forward by d.
+func (e *Emulator) Advance(d time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.advance(d)
+}
+
+// FailFrames answers the next n frames with the frame status FrameStatusBad or FrameStatusNotReady without executing
+// their commands, as the PM does with a frame corrupted on the way or sent while it is busy.
+func (e *Emulator) FailFrames(status byte, n int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.failStatus, e.failFrames = status, n
+}
+
+// respond processes the frames of a report and returns the response frames.
+func (e *Emulator) respond(r Report) [][]byte {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !e.cfg.Manual {
+		now := time.Now()
+		e.advance(time.Duration(float64(now.Sub(e.lastSync)) * e.cfg.Speed))
+		e.lastSync = now
+	}
+
+	frames := csafe.ParseCommandFrames(e.log, r.Data)
+	if len(frames) == 0 {
+		if isPadding(r.Data) {
+			return nil
+		}
+		// Nothing could be decoded, e.g. because of a checksum error.
+		return [][]byte{e.frame(csafe.FramingExtended, FrameStatusBad, nil)}
+	}
+
+	var replies [][]byte
+	for _, f := range frames {
+		if e.failFrames > 0 {
+			e.failFrames--
+			replies = append(replies, e.frame(f.Framing, e.failStatus, nil))
+			continue
+		}
+
+		status := byte(FrameStatusOk)
+		var responses []csafe.Response
+		for _, c := range f.Commands {
+			resp, ok := e.command(c)
+			if !ok {
+				status = FrameStatusReject
+				break
+			}
+			if resp != nil {
+				responses = append(responses, *resp)
+			}
+		}
+		replies = append(replies, e.frame(f.Framing, status, responses))
+	}
+	return replies
+}
+
+func isPadding(b []byte) bool {
+	for _, c := range b {
+		if c != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// frame encodes a response frame, flipping the frame toggle.
+func (e *Emulator) frame(framing csafe.Framing, status byte, responses []csafe.Response) []byte {
+	e.toggle ^= FrameToggleOn
+	return csafe.EncodeResponseFrame(framing, e.toggle|status|e.machineState, responses)
+}
+
+// report wraps a response frame in a report with the ID of the request, or in the large report if it doesn't fit.
+func (e *Emulator) report(id byte, frame []byte) Report {
+	length, ok := reportLengths[id]
+	if !ok || len(frame) > length-1 {
+		id, length = 0x04, reportLengths[0x04]
+	}
+
+	data := make([]byte, length-1)
+	copy(data, frame)
+	return Report{ID: id, Data: data}
+}
+
+// command executes a standard command. It returns a nil response for commands without one, and false if the command
+// is rejected.
+func (e *Emulator) command(c csafe.Request) (*csafe.Response, bool) {
+	if isWrapper(c.Command) {
+		nested, err := csafe.ParseCommands(c.Data)
+		if err != nil {
+			return nil, false
+		}
+
+		var data []byte
+		for _, n := range nested {
+			d, ok := e.pmCommand(n)
+			if !ok {
+				return nil, false
+			}
+			// The commands of set wrappers are acknowledged by ID alone.
+			if c.Command == csafe_SETPMCFG_CMD || c.Command == csafe_SETPMDATA_CMD {
+				data = append(data, n.Command)
+				continue
+			}
+			data = append(data, n.Command, byte(len(d)))
+			data = append(data, d...)
+		}
+		return &csafe.Response{Command: c.Command, DataByteCount: byte(len(data)), Data: data}, true
+	}
+
+	if !e.transition(c.Command) {
+		return nil, false
+	}
+
+	data, ok := e.get(c.Command)
+	if !ok {
+		return nil, false
+	}
+	if data == nil {
+		return nil, true
+	}
+	return &csafe.Response{Command: c.Command, DataByteCount: byte(len(data)), Data: data}, true
+}
+
+// transition applies the CSAFE state commands. It returns false for a state change the machine state does not allow,
+// and true for every other command.
+func (e *Emulator) transition(command byte) bool {
+	s := e.machineState
+	switch command {
+	case csafe_RESET_CMD:
+		e.machineState = MachineStateReady
+		e.arm(e.program)
+	case csafe_GOIDLE_CMD:
+		e.machineState = MachineStateIdle
+	case csafe_GOHAVEID_CMD:
+		if s != MachineStateIdle {
+			return false
+		}
+		e.machineState = MachineStateHaveID
+	case csafe_BADID_CMD:
+		if s != MachineStateHaveID {
+			return false
+		}
+		e.machineState = MachineStateIdle
+	case csafe_GOINUSE_CMD:
+		if s != MachineStateHaveID {
+			return false
+		}
+		e.machineState = MachineStateInUse
+	case csafe_GOFINISHED_CMD:
+		if s != MachineStateInUse && s != MachineStatePause {
+			return false
+		}
+		e.machineState = MachineStateFinish
+		e.workout.terminate(e.clock)
+	case csafe_GOREADY_CMD:
+		e.machineState = MachineStateReady
+	}
+	return true
+}
+
+// get returns the response data of a standard command, nil for commands without response data, and false for
+// unsupported commands.
+func (e *Emulator) get(command byte) ([]byte, bool) {
+	w := e.workout
+	switch command {
+	case csafe_GETSTATUS_CMD, csafe_RESET_CMD, csafe_GOIDLE_CMD, csafe_GOHAVEID_CMD, csafe_BADID_CMD,
+		csafe_GOINUSE_CMD, csafe_GOFINISHED_CMD, csafe_GOREADY_CMD:
+		return nil, true
+	case csafe_GETVERSION_CMD:
+		b := []byte{emulatorManufacturerID, emulatorClassID, emulatorModel}
+		b = binary.LittleEndian.AppendUint16(b, emulatorHardwareVersion)
+		return binary.LittleEndian.AppendUint16(b, emulatorFirmwareVersion), true
+	case csafe_GETID_CMD:
+		return []byte(emulatorUserID), true
+	case csafe_GETUNITS_CMD:
+		return []byte{unitsMetric}, true
+	case csafe_GETSERIAL_CMD:
+		return []byte(e.cfg.SerialNumber), true
+	case csafe_GETODOMETER_CMD:
+		return append(binary.LittleEndian.AppendUint32(nil, uint32(e.odometer)), DistanceUnitsMeter), true
+	case csafe_GETERRORCODE_CMD:
+		return []byte{0, 0, 0}, true
+	case csafe_GETTWORK_CMD:
+		t := w.workTime
+		return []byte{byte(t / time.Hour), byte(t % time.Hour / time.Minute), byte(t % time.Minute / time.Second)}, true
+	case csafe_GETHORIZONTAL_CMD:
+		return append(uint16Bytes(w.meters), DistanceUnitsMeter), true
+	case csafe_GETCALORIES_CMD:
+		return uint16Bytes(w.calories), true
+	case csafe_GETPACE_CMD:
+		return append(uint16Bytes(2*w.lastPace().Seconds()), PaceUnitsSecondsPerKilometer), true
+	case csafe_GETCADENCE_CMD:
+		return append(uint16Bytes(float64(w.lastStrokeRate())), cadenceUnitsPerMinute), true
+	case csafe_GETHRCUR_CMD:
+		return []byte{byte(e.cfg.HeartRate)}, true
+	case csafe_GETPOWER_CMD:
+		return append(uint16Bytes(float64(w.lastStroke.Watts)), PowerUnitsWatts), true
+	}
+	return nil, false
+}
+
+// pmCommand executes a PM-specific command nested in a wrapper and returns its response data. It returns false for
+// unsupported commands.
+func (e *Emulator) pmCommand(c csafe.Request) ([]byte, bool) {
+	if ok, handled := e.pmSet(c); handled {
+		return nil, ok
+	}
+
+	w := e.workout
+	switch c.Command {
+	case csafe_PM_GET_WORKOUTSTATE:
+		return []byte{byte(w.state)}, true
+	case csafe_PM_GET_WORKOUTTYPE:
+		return []byte{byte(w.workoutType)}, true
+	case csafe_PM_GET_WORKOUTINTERVALCOUNT:
+		return []byte{byte(len(w.intervals))}, true
+	case csafe_PM_GET_STROKESTATE:
+		return []byte{byte(w.strokeState)}, true
+	case csafe_PM_GET_WORKTIME:
+		return append(binary.LittleEndian.AppendUint32(nil, uint32(w.workTime/(10*time.Millisecond))), 0), true
+	case csafe_PM_GET_WORKDISTANCE:
+		return append(binary.LittleEndian.AppendUint32(nil, uint32(w.meters*10)), 0), true
+	case csafe_PM_GET_STROKE500MPACE:
+		return binary.LittleEndian.AppendUint32(nil, uint32(w.lastPace()/(10*time.Millisecond))), true
+	case csafe_PM_GET_STROKERATE:
+		return []byte{byte(w.lastStrokeRate())}, true
+	case csafe_PM_GET_DRAGFACTOR:
+		return []byte{byte(w.dragFactor)}, true
+	case csafe_PM_GET_STROKESTATS:
+		return strokeStatsBytes(w.lastStroke), true
+	case csafe_PM_GET_FORCEPLOTDATA:
+		return blockBytes(&w.forceCurve, c.Data), true
+	case csafe_PM_GET_HEARTBEATDATA:
+		return blockBytes(&e.heartbeats, c.Data), true
+	}
+
+	// The split commands report the split or interval that ended last.
+	var interval IntervalSummary
+	if n := len(w.intervals); n > 0 {
+		interval = w.intervals[n-1]
+	}
+
+	switch c.Command {
+	case csafe_PM_GET_LASTSPLITTIME:
+		return binary.LittleEndian.AppendUint32(nil, uint32(interval.Time/(10*time.Millisecond))), true
+	case csafe_PM_GET_LASTSPLITDISTANCE:
+		return binary.LittleEndian.AppendUint32(nil, uint32(interval.Meters)), true
+	case csafe_PM_GET_SPLITAVG500MPACE:
+		return binary.LittleEndian.AppendUint32(nil, uint32(interval.Pace/(10*time.Millisecond))), true
+	case csafe_PM_GET_SPLITAVGSTROKERATE:
+		return []byte{byte(interval.StrokeRate)}, true
+	case csafe_PM_GET_AVGHEARTRATE:
+		if interval.HeartRate == 0 {
+			return []byte{255}, true
+		}
+		return []byte{byte(interval.HeartRate)}, true
+	case csafe_PM_GET_RESTTIME:
+		return binary.LittleEndian.AppendUint16(nil, uint16(interval.Rest/time.Second)), true
+	}
+
+	return nil, false
+}
+
+// pmSet executes the PM-specific workout configuration commands. handled is false for other commands.
+func (e *Emulator) pmSet(c csafe.Request) (ok, handled bool) {
+	interval := func() *Interval {
+		for len(e.program.Intervals) <= e.selected {
+			e.program.Intervals = append(e.program.Intervals, Interval{})
+		}
+		return &e.program.Intervals[e.selected]
+	}
+	variable := func() bool {
+		return len(e.program.Intervals) > 0
+	}
+
+	switch c.Command {
+	case csafe_PM_SET_WORKOUTTYPE:
+		if len(c.Data) < 1 {
+			return false, true
+		}
+		// Programming starts over with the workout type.
+		e.program = Workout{}
+		if t := WorkoutType(c.Data[0]); t == WorkoutTypeVariableInterval || t == WorkoutTypeVariableUndefinedRestInterval {
+			interval()
+		}
+
+	case csafe_PM_SET_WORKOUTDURATION, csafe_PM_SET_SPLITDURATION:
+		if len(c.Data) < 5 {
+			return false, true
+		}
+		d := WorkoutDuration{Type: DurationType(c.Data[0]), Value: binary.BigEndian.Uint32(c.Data[1:5])}
+		switch {
+		case c.Command == csafe_PM_SET_SPLITDURATION:
+			e.program.Split = d
+		case variable():
+			interval().Duration = d
+		default:
+			e.program.Duration = d
+		}
+
+	case csafe_PM_SET_RESTDURATION:
+		if len(c.Data) < 2 {
+			return false, true
+		}
+		rest := time.Duration(binary.BigEndian.Uint16(c.Data)) * time.Second
+		if variable() {
+			interval().Rest = rest
+		} else {
+			e.program.Rest = rest
+		}
+
+	case csafe_PM_SET_TARGETPACETIME:
+		if len(c.Data) < 4 {
+			return false, true
+		}
+		pace := time.Duration(binary.BigEndian.Uint32(c.Data)) * 10 * time.Millisecond
+		if variable() {
+			interval().TargetPace = pace
+		} else {
+			e.program.TargetPace = pace
+		}
+
+	case csafe_PM_SET_WORKOUTINTERVALCOUNT:
+		if len(c.Data) < 1 {
+			return false, true
+		}
+		e.selected = int(c.Data[0])
+
+	case csafe_PM_SET_INTERVALTYPE, csafe_PM_CONFIGURE_WORKOUT:
+		// The interval type follows from the duration type, and programming mode is always enabled.
+
+	case csafe_PM_SET_SCREENSTATE:
+		if len(c.Data) < 2 || ScreenType(c.Data[0]) != ScreenTypeWorkout {
+			return true, true
+		}
+		switch ScreenValue(c.Data[1]) {
+		case ScreenValueWorkoutPrepareToRow:
+			e.arm(e.program)
+		case ScreenValueWorkoutTerminate, ScreenValueWorkoutTerminateLogin:
+			e.workout.terminate(e.clock)
+		case ScreenValueWorkoutRearm:
+			e.arm(e.workout.workout)
+		}
+
+	default:
+		return false, false
+	}
+
+	return true, true
+}
+
+// arm gets a workout ready to row.
+func (e *Emulator) arm(w Workout) {
+	e.workout = newEmulatedWorkout(w, e.cfg.FixedIntervals, e.clock+e.cfg.StartAfter)
+	e.selected = 0
+	if e.machineState == MachineStateFinish {
+		e.machineState = MachineStateReady
+	}
+}
+
+// advance runs the simulation for d.
+func (e *Emulator) advance(d time.Duration) {
+	for ; d > 0; d -= emulatorTick {
+		dt := min(d, emulatorTick)
+		e.clock += dt
+
+		w := e.workout
+		started, ended := w.started(), w.ended()
+		meters := w.meters
+		w.step(e.clock, dt, e.cfg.Rower, e.cfg.HeartRate)
+		e.odometer += w.meters - meters
+
+		if !started && w.started() && e.machineState != MachineStateInUse {
+			e.machineState = MachineStateInUse
+		}
+		if !ended && w.ended() && e.machineState == MachineStateInUse {
+			e.machineState = MachineStateFinish
+		}
+
+		e.beat()
+	}
+}
+
+// maxHeartbeats is the number of beats the emulated belt buffers until they are read.
+const maxHeartbeats = 64
+
+// beat records the beats of the emulated heart rate belt, in 1/1024 s.
+func (e *Emulator) beat() {
+	if e.cfg.HeartRate <= 0 || e.clock < e.nextBeat {
+		return
+	}
+
+	e.heartbeats = append(e.heartbeats, int(uint16(e.clock*1024/time.Second)))
+	if len(e.heartbeats) > maxHeartbeats {
+		e.heartbeats = e.heartbeats[1:]
+	}
+	e.nextBeat = e.clock + time.Minute/time.Duration(e.cfg.HeartRate)
+}
+
+// blockBytes removes up to the requested number of bytes of samples from the buffer and encodes them as returned by
+// CSAFE_PM_GET_FORCEPLOTDATA and CSAFE_PM_GET_HEARTBEATDATA.
+func blockBytes(samples *[]int, request []byte) []byte {
+	n := blockLength
+	if len(request) > 0 {
+		n = min(int(request[0]), blockLength)
+	}
+	n = min(n/2, len(*samples))
+
+	b := []byte{byte(2 * n)}
+	for _, s := range (*samples)[:n] {
+		b = binary.LittleEndian.AppendUint16(b, uint16(s))
+	}
+	*samples = (*samples)[n:]
+
+	// The response always has room for a full block.
+	return append(b, make([]byte, blockLength-2*n)...)
+}
+
+func strokeStatsBytes(s Stroke) []byte {
+	driveTime := s.DriveTime / (10 * time.Millisecond)
+	b := binary.LittleEndian.AppendUint16(nil, uint16(math.Round(s.Distance*100)))
+	b = append(b, byte(driveTime))
+	b = binary.LittleEndian.AppendUint16(b, uint16(s.RecoveryTime/(10*time.Millisecond)))
+	b = append(b, byte(math.Round(s.Length*100)))
+	b = binary.LittleEndian.AppendUint16(b, uint16(s.DriveCounter))
+	b = binary.LittleEndian.AppendUint16(b, uint16(math.Round(s.PeakForce*10)))
+	b = binary.LittleEndian.AppendUint16(b, uint16(math.Round(s.AverageForce*s.DriveTime.Seconds()*10)))
+	b = binary.LittleEndian.AppendUint16(b, uint16(math.Round(s.AverageForce*10)))
+	work := float64(s.Watts) * (s.DriveTime + s.RecoveryTime).Seconds()
+	return binary.LittleEndian.AppendUint16(b, uint16(math.Round(work*10)))
+}
+
+// uint16Bytes encodes a value rounded to an unsigned 16 bit little-endian integer, saturating at its limits.
+func uint16Bytes(v float64) []byte {
+	return binary.LittleEndian.AppendUint16(nil, uint16(math.Round(min(max(v, 0), math.MaxUint16))))
+}
@@ -0,0 +1,284 @@
+package pm5
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/seagrayinc/gorow/internal/csafe"
+)
+
+// emulatedPM5 returns a PM5 connected to an emulator that only advances with Advance.
+func emulatedPM5(t *testing.T, ctx context.Context, cfg EmulatorConfig) (*PM5, *Emulator) {
+	t.Helper()
+
+	cfg.Manual = true
+	e := NewEmulator(cfg)
+	p, err := Open(ctx, WithDevice(e))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	return p, e
+}
+
+func TestEmulatorJustRow(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	p, e := emulatedPM5(t, ctx, EmulatorConfig{HeartRate: 150, StartAfter: 5 * time.Second})
+
+	state, err := Query[GetWorkoutStateResponse](ctx, p, GetWorkoutState())
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if state.WorkoutState != 0 {
+		t.Errorf("workout state before rowing: got %d, want 0", state.WorkoutState)
+	}
+
+	e.Advance(65 * time.Second)
+
+	responses, err := p.exchange(ctx,
+		GetStatus(), GetHRCur(), GetCadence(), GetPower(), GetHorizontal(), GetWorkoutState(),
+		GetWorkTime(), GetWorkDistance(), GetStroke500mPace(), GetDragFactor(), GetCalories(),
+	)
+	if err != nil {
+		t.Fatalf("exchange failed: %v", err)
+	}
+
+	status, _ := find[GetStatusResponse](responses)
+	if status.StateMachineState != MachineStateInUse {
+		t.Errorf("machine state: got %d, want in use", status.StateMachineState)
+	}
+	if s, _ := find[GetWorkoutStateResponse](responses); s.WorkoutState != 1 {
+		t.Errorf("workout state: got %d, want 1", s.WorkoutState)
+	}
+	if hr, _ := find[GetHRCurResponse](responses); hr.BeatsPerMinute != 150 {
+		t.Errorf("heart rate: got %d, want 150", hr.BeatsPerMinute)
+	}
+	if c, _ := find[GetCadenceResponse](responses); c.StrokesPerMinute != 24 {
+		t.Errorf("stroke rate: got %d, want 24", c.StrokesPerMinute)
+	}
+	// 2:00/500m is 2.80 / 0.24^3 W
+	if pw, _ := find[GetPowerResponse](responses); pw.StrokeWatts != 203 {
+		t.Errorf("power: got %d, want 203", pw.StrokeWatts)
+	}
+	if wt, _ := find[GetWorkTimeResponse](responses); wt.WorkTime != time.Minute {
+		t.Errorf("work time: got %v, want 1m", wt.WorkTime)
+	}
+	if d, _ := find[GetWorkDistanceResponse](responses); math.Abs(d.Meters-250) > 0.1 {
+		t.Errorf("work distance: got %v, want 250", d.Meters)
+	}
+	if h, _ := find[GetHorizontalResponse](responses); h.Meters != 250 {
+		t.Errorf("horizontal distance: got %v, want 250", h.Meters)
+	}
+	if pace, _ := find[GetStroke500mPaceResponse](responses); pace.Per500m != 2*time.Minute {
+		t.Errorf("pace: got %v, want 2m0s", pace.Per500m)
+	}
+	if df, _ := find[GetDragFactorResponse](responses); df.DragFactor != 120 {
+		t.Errorf("drag factor: got %d, want 120", df.DragFactor)
+	}
+	// A minute at 203 W is (4 * 0.8604 * 203 + 300) / 60 kcal
+	if c, _ := find[GetCaloriesResponse](responses); c.Calories != 17 {
+		t.Errorf("calories: got %d, want 17", c.Calories)
+	}
+
+	stats, err := Query[GetStrokeStatsResponse](ctx, p, GetStrokeStats())
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if stats.DriveCounter != 24 || stats.StrokeLength != 140 || stats.StrokeDriveTime != 83 {
+		t.Errorf("stroke stats mismatch: %+v", stats)
+	}
+
+	curve, err := Query[GetForcePlotDataResponse](ctx, p, GetForcePlotData())
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(curve.Samples) != 16 || curve.Samples[7] <= curve.Samples[0] {
+		t.Errorf("force curve samples: %v", curve.Samples)
+	}
+
+	beats, err := Query[GetHeartbeatDataResponse](ctx, p, GetHeartbeatData())
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(beats.BeatTimes) != 16 || beats.BeatTimes[1]-beats.BeatTimes[0] != 410 {
+		t.Errorf("heartbeat times: %v", beats.BeatTimes)
+	}
+}
+
+func TestEmulatorProgramWorkout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tests := []struct {
+		name    string
+		workout Workout
+		want    IntervalSummary // The last interval
+	}{
+		{
+			name:    "fixed distance",
+			workout: Workout{Duration: Meters(500)},
+			want:    IntervalSummary{Time: 2 * time.Minute, Meters: 500, Pace: 2 * time.Minute, StrokeRate: 24},
+		},
+		{
+			name:    "fixed time intervals",
+			workout: Workout{Duration: Time(time.Minute), Rest: 30 * time.Second},
+			want:    IntervalSummary{Time: time.Minute, Meters: 250, Pace: 2 * time.Minute, StrokeRate: 24, Rest: 30 * time.Second},
+		},
+		{
+			name: "variable intervals",
+			workout: Workout{Intervals: []Interval{
+				{Duration: Meters(250), Rest: time.Minute},
+				{Duration: Time(2 * time.Minute)},
+			}},
+			want: IntervalSummary{Time: 2 * time.Minute, Meters: 500, Pace: 2 * time.Minute, StrokeRate: 24},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, e := emulatedPM5(t, ctx, EmulatorConfig{FixedIntervals: 2})
+
+			if err := ProgramWorkout(ctx, p, tt.workout); err != nil {
+				t.Fatalf("ProgramWorkout failed: %v", err)
+			}
+			e.Advance(10 * time.Minute)
+
+			state, err := Query[GetWorkoutStateResponse](ctx, p, GetWorkoutState())
+			if err != nil {
+				t.Fatalf("Query failed: %v", err)
+			}
+			if state.WorkoutState != 10 {
+				t.Errorf("workout state: got %d, want 10", state.WorkoutState)
+			}
+
+			responses, err := p.exchange(ctx, GetWorkoutType(), getLastSplit())
+			if err != nil {
+				t.Fatalf("exchange failed: %v", err)
+			}
+			wantType, _ := tt.workout.workoutType()
+			if workoutType, _ := find[GetWorkoutTypeResponse](responses); workoutType.WorkoutType != wantType {
+				t.Errorf("workout type: got %d, want %d", workoutType.WorkoutType, wantType)
+			}
+			got, ok := lastSplit(responses)
+			if !ok {
+				t.Fatalf("last split missing from %+v", responses)
+			}
+			want := tt.want
+			// Distance pieces end on the tick that crosses the distance.
+			if got.Time-want.Time > emulatorTick || got.Meters != want.Meters || got.StrokeRate != want.StrokeRate ||
+				got.Rest != want.Rest || (got.Pace-want.Pace).Abs() > 10*time.Millisecond {
+				t.Errorf("interval mismatch:\ngot:  %+v\nwant: %+v", got, want)
+			}
+
+			// Selecting an interval only programs it; the split commands keep reporting the last one.
+			if _, err := p.exchange(ctx, setPMCfg(setWorkoutIntervalCount(0))); err != nil {
+				t.Fatalf("SetWorkoutIntervalCount failed: %v", err)
+			}
+			split, err := Query[GetLastSplitTimeResponse](ctx, p, GetLastSplitTime())
+			if err != nil {
+				t.Fatalf("Query failed: %v", err)
+			}
+			if split.SplitTime != got.Time {
+				t.Errorf("last split time: got %v, want %v", split.SplitTime, got.Time)
+			}
+		})
+	}
+}
+
+func TestEmulatorStates(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	p, _ := emulatedPM5(t, ctx, EmulatorConfig{StartAfter: time.Hour})
+
+	var statusErr *FrameStatusError
+	if _, err := p.exchange(ctx, GoInUse()); !errors.As(err, &statusErr) || statusErr.Status != FrameStatusReject {
+		t.Fatalf("GoInUse from ready: got %v, want a rejected frame", err)
+	}
+
+	for _, step := range []struct {
+		command Command
+		want    byte
+	}{
+		{GoIdle(), MachineStateIdle},
+		{GoHaveID(), MachineStateHaveID},
+		{GoInUse(), MachineStateInUse},
+		{GoFinished(), MachineStateFinish},
+		{Reset(), MachineStateReady},
+	} {
+		responses, err := p.exchange(ctx, step.command)
+		if err != nil {
+			t.Fatalf("exchange failed: %v", err)
+		}
+		if status, _ := find[GetStatusResponse](responses); status.StateMachineState != step.want {
+			t.Errorf("machine state: got %d, want %d", status.StateMachineState, step.want)
+		}
+	}
+}
+
+func TestEmulatorBadFrame(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	e := NewEmulator(EmulatorConfig{Manual: true})
+	reports := e.PollReports(ctx)
+
+	// The checksum of a GetStatus frame is 0x80.
+	go e.WriteReport(ctx, Report{ID: 0x02, Data: []byte{csafe.StandardFrameStartFlag, 0x80, 0x00, csafe.StopFrameFlag}})
+
+	frames := csafe.ParseFrames(slog.Default(), (<-reports).Data)
+	if len(frames) != 1 {
+		t.Fatalf("frame count mismatch: got %d, want 1", len(frames))
+	}
+	if got := frames[0].ResponseStatus.PreviousFrameStatus; got != FrameStatusBad {
+		t.Errorf("frame status: got 0x%02X, want 0x%02X", got, FrameStatusBad)
+	}
+}
+
+func TestEmulatorFailFrames(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	p, e := emulatedPM5(t, ctx, EmulatorConfig{StartAfter: time.Hour})
+
+	// The transport resends the frame until the PM takes it, and its commands are executed once: GoHaveID is only
+	// allowed from the idle state.
+	for _, step := range []struct {
+		status  byte
+		command Command
+		want    byte
+	}{
+		{FrameStatusNotReady, GoIdle(), MachineStateIdle},
+		{FrameStatusBad, GoHaveID(), MachineStateHaveID},
+	} {
+		e.FailFrames(step.status, 2)
+		responses, err := p.exchange(ctx, step.command, GetOdometer())
+		if err != nil {
+			t.Fatalf("exchange after 0x%02X frames failed: %v", step.status, err)
+		}
+		if s, _ := find[GetStatusResponse](responses); s.StateMachineState != step.want {
+			t.Errorf("machine state: got %d, want %d", s.StateMachineState, step.want)
+		}
+		if _, ok := find[GetOdometerResponse](responses); !ok {
+			t.Errorf("odometer missing from %+v", responses)
+		}
+	}
+
+	// Once the retries are exhausted, the request fails with the last status.
+	e.FailFrames(FrameStatusBad, 4)
+	var statusErr *FrameStatusError
+	if _, err := Query[GetSerialResponse](ctx, p, GetSerial()); !errors.As(err, &statusErr) ||
+		statusErr.Status != FrameStatusBad || statusErr.Attempts != 4 {
+		t.Fatalf("Query: got %v, want a bad frame after 4 attempts", err)
+	}
+
+	// The failures are used up, so the PM answers again.
+	if _, err := Query[GetSerialResponse](ctx, p, GetSerial()); err != nil {
+		t.Errorf("Query failed: %v", err)
+	}
+}
@@ -0,0 +1,272 @@
+package pm5
+
+import (
+	"math"
+	"time"
+)
+
+// transitionTime is how long an emulated PM reports the transition states between work and rest.
+const transitionTime = time.Second
+
+// emulatedSegment is the work part of an interval, followed by its rest. Just Row is a single segment without a
+// duration.
+type emulatedSegment struct {
+	duration WorkoutDuration
+	rest     time.Duration
+}
+
+// emulatedWorkout is the state of a workout rowed on an Emulator. Metrics are cumulative over the whole workout.
+type emulatedWorkout struct {
+	workout     Workout
+	workoutType WorkoutType
+	segments    []emulatedSegment
+	startAt     time.Duration // Emulator clock at which the rower starts
+
+	state       int // Workout state, one of the keys of WorkoutStateMap
+	current     int // Index of the current segment
+	resting     bool
+	phaseStart  time.Duration // Emulator clock at which the current work or rest started
+	strokeState int
+	dragFactor  int
+
+	workTime time.Duration
+	meters   float64
+	calories float64
+	joules   float64
+
+	lastStroke Stroke
+	forceCurve []int
+
+	segment   emulatedSegmentTotals
+	intervals []IntervalSummary // Completed intervals
+}
+
+// emulatedSegmentTotals accumulates the work part of the current segment.
+type emulatedSegmentTotals struct {
+	time       time.Duration
+	meters     float64
+	calories   float64
+	joules     float64
+	strokes    int
+	strokeTime time.Duration // Total time of the strokes, for the average stroke rate
+	beats      float64       // Heart rate integrated over time, for the average heart rate
+}
+
+func newEmulatedWorkout(w Workout, fixedIntervals int, startAt time.Duration) *emulatedWorkout {
+	workoutType, _ := w.workoutType()
+
+	var segments []emulatedSegment
+	switch {
+	case len(w.Intervals) > 0:
+		for _, i := range w.Intervals {
+			segments = append(segments, emulatedSegment{duration: i.Duration, rest: i.Rest})
+		}
+	case w.Rest > 0:
+		for range fixedIntervals {
+			segments = append(segments, emulatedSegment{duration: w.Duration, rest: w.Rest})
+		}
+	default:
+		segments = []emulatedSegment{{duration: w.Duration}}
+	}
+
+	return &emulatedWorkout{
+		workout:     w,
+		workoutType: workoutType,
+		segments:    segments,
+		startAt:     startAt,
+	}
+}
+
+func (w *emulatedWorkout) intervalWorkout() bool {
+	return len(w.workout.Intervals) > 0 || w.workout.Rest > 0
+}
+
+func (w *emulatedWorkout) started() bool {
+	return w.state != 0
+}
+
+func (w *emulatedWorkout) ended() bool {
+	return w.state == 10 || w.state == 11
+}
+
+// step runs the workout for dt, ending at the emulator clock now.
+func (w *emulatedWorkout) step(now, dt time.Duration, rower Rower, heartRate int) {
+	if w.ended() || now <= w.startAt {
+		w.idle(rower, dt)
+		return
+	}
+
+	if !w.started() {
+		w.phaseStart = now - dt
+		w.state = w.workState()
+	}
+
+	if w.resting {
+		w.idle(rower, dt)
+		w.rest(now)
+		return
+	}
+
+	s := rower.Row(dt, true)
+	w.strokeState, w.dragFactor = s.StrokeState, s.DragFactor
+	w.workTime += dt
+	w.meters += s.Meters
+	w.joules += s.Watts * dt.Seconds()
+	w.calories += strokeCalories(s.Watts, dt)
+
+	seg := &w.segment
+	seg.time += dt
+	seg.meters += s.Meters
+	seg.joules += s.Watts * dt.Seconds()
+	seg.calories += strokeCalories(s.Watts, dt)
+	seg.beats += float64(heartRate) * dt.Seconds()
+
+	if s.Stroke != nil {
+		stroke := *s.Stroke
+		stroke.DriveCounter = w.lastStroke.DriveCounter + 1
+		w.lastStroke = stroke
+		w.forceCurve = s.ForceCurve
+		seg.strokes++
+		seg.strokeTime += stroke.DriveTime + stroke.RecoveryTime
+	}
+
+	if w.segmentDone() {
+		w.endSegment(now)
+	}
+}
+
+// idle lets the flywheel of the rower spin down outside of work.
+func (w *emulatedWorkout) idle(rower Rower, dt time.Duration) {
+	s := rower.Row(dt, false)
+	w.strokeState, w.dragFactor = s.StrokeState, s.DragFactor
+}
+
+// strokeCalories returns the calories burned rowing at the given power for dt, using the Concept2 formula: four times
+// the power in kcal per hour (0.8604 kcal/h per watt), plus 300 kcal per hour.
+func strokeCalories(watts float64, dt time.Duration) float64 {
+	return (4*0.8604*watts + 300) * dt.Hours()
+}
+
+func (w *emulatedWorkout) segmentDone() bool {
+	d := w.segments[w.current].duration
+	switch {
+	case d.IsZero():
+		return false
+	case d.Type == DurationTypeTime:
+		return w.segment.time >= time.Duration(d.Value)*10*time.Millisecond
+	case d.Type == DurationTypeDistance:
+		return w.segment.meters >= float64(d.Value)
+	case d.Type == DurationTypeCalories:
+		return w.segment.calories >= float64(d.Value)
+	case d.Type == DurationTypeWattMinutes:
+		return w.segment.joules/60 >= float64(d.Value)
+	}
+	return false
+}
+
+// endSegment records the interval that just finished and moves to its rest, the next interval or the end of the
+// workout.
+func (w *emulatedWorkout) endSegment(now time.Duration) {
+	seg := w.segment
+	interval := IntervalSummary{
+		Time:   seg.time,
+		Meters: int(math.Round(seg.meters)),
+		Rest:   w.segments[w.current].rest,
+	}
+	if seg.meters > 0 {
+		interval.Pace = time.Duration(float64(seg.time) * 500 / seg.meters).Round(10 * time.Millisecond)
+	}
+	if seg.strokeTime > 0 {
+		interval.StrokeRate = int(float64(seg.strokes) * float64(time.Minute) / float64(seg.strokeTime))
+	}
+	if seg.time > 0 {
+		interval.HeartRate = int(seg.beats / seg.time.Seconds())
+	}
+	w.intervals = append(w.intervals, interval)
+	w.segment = emulatedSegmentTotals{}
+	w.phaseStart = now
+
+	switch {
+	case w.current == len(w.segments)-1:
+		w.state = 10
+		w.strokeState = StrokeStateWaitingForWheelToReachMinSpeed
+	case interval.Rest > 0:
+		w.resting = true
+		w.state = w.toRestState()
+	default:
+		w.current++
+		w.state = w.workState()
+	}
+}
+
+// rest moves through the states of a rest and on to the next interval once it is over.
+func (w *emulatedWorkout) rest(now time.Duration) {
+	rest := w.segments[w.current].rest
+	elapsed := now - w.phaseStart
+
+	switch {
+	case elapsed >= rest:
+		w.resting = false
+		w.current++
+		w.phaseStart = now
+		w.state = w.workState()
+	case elapsed >= rest-transitionTime:
+		w.state = 6
+		if w.segments[w.current+1].duration.Type != DurationTypeTime {
+			w.state = 7
+		}
+	case elapsed >= transitionTime:
+		w.state = 3
+	}
+}
+
+// workState is the workout state while working on the current segment.
+func (w *emulatedWorkout) workState() int {
+	switch {
+	case !w.intervalWorkout():
+		return 1
+	case w.segments[w.current].duration.Type == DurationTypeTime:
+		return 4
+	default:
+		return 5
+	}
+}
+
+// toRestState is the workout state at the end of the work of the current segment.
+func (w *emulatedWorkout) toRestState() int {
+	if w.segments[w.current].duration.Type == DurationTypeTime {
+		return 8
+	}
+	return 9
+}
+
+// terminate stops a workout in progress.
+func (w *emulatedWorkout) terminate(now time.Duration) {
+	if w.ended() {
+		return
+	}
+	if w.started() && !w.resting && w.segment.time > 0 {
+		w.endSegment(now)
+	}
+	w.state = 11
+	w.resting = false
+	w.strokeState = StrokeStateWaitingForWheelToReachMinSpeed
+}
+
+// lastPace is the pace per 500 m of the last stroke.
+func (w *emulatedWorkout) lastPace() time.Duration {
+	s := w.lastStroke
+	if s.Distance <= 0 {
+		return 0
+	}
+	return time.Duration(float64(s.DriveTime+s.RecoveryTime) * 500 / s.Distance).Round(10 * time.Millisecond)
+}
+
+// lastStrokeRate is the stroke rate of the last stroke.
+func (w *emulatedWorkout) lastStrokeRate() int {
+	t := w.lastStroke.DriveTime + w.lastStroke.RecoveryTime
+	if t <= 0 {
+		return 0
+	}
+	return int(time.Minute / t)
+}
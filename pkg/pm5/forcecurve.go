@@ -0,0 +1,118 @@
+package pm5
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// ForceCurve is the force curve of a single drive.
+type ForceCurve struct {
+	DriveCounter int   // DriveCounter of the GetStrokeStatsResponse for the same stroke
+	Samples      []int // Force in lbs, sampled evenly across the drive
+}
+
+// ForceCurveStream polls the PM and emits the force curve of every stroke. It queries the stroke state every
+// interval; once the rower is driving it reads force plot blocks until the PM has none left, and when the drive is
+// over it reads the stroke stats and emits the stitched curve with their drive counter. Failed queries are logged
+// and polling continues. The channel is closed when ctx is done.
+//
+// The responses to the queries are emitted on the event stream as usual.
+func ForceCurveStream(ctx context.Context, p *PM5, interval time.Duration) <-chan ForceCurve {
+	r := forceCurveReader{
+		strokeState: func(ctx context.Context) (GetStrokeStateResponse, error) {
+			return Query[GetStrokeStateResponse](ctx, p, GetStrokeState())
+		},
+		forcePlotData: func(ctx context.Context) (GetForcePlotDataResponse, error) {
+			return Query[GetForcePlotDataResponse](ctx, p, GetForcePlotData())
+		},
+		strokeStats: func(ctx context.Context) (GetStrokeStatsResponse, error) {
+			return Query[GetStrokeStatsResponse](ctx, p, GetStrokeStats())
+		},
+	}
+
+	out := make(chan ForceCurve)
+	go func() {
+		defer close(out)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			curve, ok, err := r.poll(ctx)
+			if err != nil && ctx.Err() == nil {
+				p.log.Warn("force curve poll failed", slog.Any("error", err))
+			}
+			if ok {
+				select {
+				case out <- curve:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// forceCurveReader stitches the force plot blocks of a drive into a ForceCurve. The queries are functions so that
+// the stitching can be tested without a PM.
+type forceCurveReader struct {
+	strokeState   func(context.Context) (GetStrokeStateResponse, error)
+	forcePlotData func(context.Context) (GetForcePlotDataResponse, error)
+	strokeStats   func(context.Context) (GetStrokeStatsResponse, error)
+
+	collecting bool  // A drive has been seen and its curve not emitted yet
+	samples    []int // Samples read so far for the current drive
+}
+
+// poll performs one polling round and reports whether a drive has finished, returning its curve.
+func (r *forceCurveReader) poll(ctx context.Context) (ForceCurve, bool, error) {
+	state, err := r.strokeState(ctx)
+	if err != nil {
+		return ForceCurve{}, false, err
+	}
+
+	driving := state.StrokeState == StrokeStateDriving
+	if !driving && !r.collecting {
+		return ForceCurve{}, false, nil
+	}
+	r.collecting = true
+
+	// Catch up with the samples the PM has buffered so far. Once the drive is over, this reads the rest of the curve.
+	for {
+		block, err := r.forcePlotData(ctx)
+		if err != nil {
+			return ForceCurve{}, false, err
+		}
+		if len(block.Samples) == 0 {
+			break
+		}
+		r.samples = append(r.samples, block.Samples...)
+	}
+
+	if driving {
+		return ForceCurve{}, false, nil
+	}
+
+	// The stroke stats are updated at the end of the drive, so they now describe the stroke the curve belongs to.
+	samples := r.samples
+	r.collecting, r.samples = false, nil
+
+	stats, err := r.strokeStats(ctx)
+	if err != nil {
+		return ForceCurve{}, false, err
+	}
+	if len(samples) == 0 {
+		return ForceCurve{}, false, nil
+	}
+
+	return ForceCurve{DriveCounter: stats.DriveCounter, Samples: samples}, true, nil
+}
@@ -0,0 +1,133 @@
+package pm5
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseGetForcePlotDataResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		want    GetForcePlotDataResponse
+		wantErr bool
+	}{
+		{
+			name: "partial block",
+			data: []byte{0x06, 0x0A, 0x00, 0x2C, 0x01, 0x00, 0x02},
+			want: GetForcePlotDataResponse{BytesRead: 6, Samples: []int{10, 300, 512}},
+		},
+		{
+			// The PM pads the block to the requested length
+			name: "padded block",
+			data: []byte{0x02, 0x0A, 0x00, 0x00, 0x00},
+			want: GetForcePlotDataResponse{BytesRead: 2, Samples: []int{10}},
+		},
+		{
+			name: "empty block",
+			data: []byte{0x00},
+			want: GetForcePlotDataResponse{BytesRead: 0, Samples: []int{}},
+		},
+		{name: "truncated", data: []byte{0x04, 0x0A, 0x00}, wantErr: true},
+		{name: "odd length", data: []byte{0x03, 0x0A, 0x00, 0x01}, wantErr: true},
+		{name: "too long", data: append([]byte{0x22}, make([]byte, 34)...), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseGetForcePlotDataResponse(tt.data)
+			if tt.wantErr {
+				if !errors.Is(err, ErrMalformedResponse) {
+					t.Errorf("expected ErrMalformedResponse, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("response mismatch:\ngot:  %+v\nwant: %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// scriptedForceCurveReader returns a forceCurveReader whose queries answer from the given scripts in order.
+func scriptedForceCurveReader(states []int, blocks [][]int, driveCounter int) *forceCurveReader {
+	return &forceCurveReader{
+		strokeState: func(context.Context) (GetStrokeStateResponse, error) {
+			state := states[0]
+			states = states[1:]
+			return GetStrokeStateResponse{StrokeState: state}, nil
+		},
+		forcePlotData: func(context.Context) (GetForcePlotDataResponse, error) {
+			if len(blocks) == 0 {
+				return GetForcePlotDataResponse{Samples: []int{}}, nil
+			}
+			block := blocks[0]
+			blocks = blocks[1:]
+			return GetForcePlotDataResponse{BytesRead: 2 * len(block), Samples: block}, nil
+		},
+		strokeStats: func(context.Context) (GetStrokeStatsResponse, error) {
+			return GetStrokeStatsResponse{DriveCounter: driveCounter}, nil
+		},
+	}
+}
+
+func TestForceCurveReader(t *testing.T) {
+	ctx := context.Background()
+
+	// Recovery, then a drive over two polls, then dwelling. An empty block marks the end of each catch-up.
+	r := scriptedForceCurveReader(
+		[]int{StrokeStateRecovery, StrokeStateDriving, StrokeStateDriving, StrokeStateDwellingAfterDrive, StrokeStateRecovery},
+		[][]int{{10, 20, 30}, {}, {40, 50}, {}, {45, 5}},
+		7,
+	)
+
+	var curves []ForceCurve
+	for range 5 {
+		curve, ok, err := r.poll(ctx)
+		if err != nil {
+			t.Fatalf("poll failed: %v", err)
+		}
+		if ok {
+			curves = append(curves, curve)
+		}
+	}
+
+	want := []ForceCurve{{DriveCounter: 7, Samples: []int{10, 20, 30, 40, 50, 45, 5}}}
+	if !reflect.DeepEqual(curves, want) {
+		t.Errorf("curves mismatch:\ngot:  %+v\nwant: %+v", curves, want)
+	}
+}
+
+func TestForceCurveReaderQueryError(t *testing.T) {
+	ctx := context.Background()
+	errQuery := errors.New("query failed")
+
+	r := scriptedForceCurveReader([]int{StrokeStateDriving, StrokeStateRecovery}, [][]int{{10, 20}}, 3)
+	forcePlotData := r.forcePlotData
+	failed := false
+	r.forcePlotData = func(ctx context.Context) (GetForcePlotDataResponse, error) {
+		// Fail once after the first block; the samples read so far are kept for the next poll.
+		if !failed && len(r.samples) > 0 {
+			failed = true
+			return GetForcePlotDataResponse{}, errQuery
+		}
+		return forcePlotData(ctx)
+	}
+
+	if _, _, err := r.poll(ctx); !errors.Is(err, errQuery) {
+		t.Fatalf("expected query error, got %v", err)
+	}
+
+	curve, ok, err := r.poll(ctx)
+	if err != nil || !ok {
+		t.Fatalf("expected a curve, got ok=%v err=%v", ok, err)
+	}
+	if want := (ForceCurve{DriveCounter: 3, Samples: []int{10, 20}}); !reflect.DeepEqual(curve, want) {
+		t.Errorf("curve mismatch:\ngot:  %+v\nwant: %+v", curve, want)
+	}
+}
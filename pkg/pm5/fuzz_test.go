@@ -0,0 +1,44 @@
+package pm5
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/seagrayinc/gorow/internal/csafe"
+)
+
+// FuzzParseResponses runs raw HID report data through the complete decode path.
+func FuzzParseResponses(f *testing.F) {
+	for _, tt := range endToEndTests {
+		f.Add(parseHexString(tt.rawHex))
+	}
+
+	log := slog.New(slog.DiscardHandler)
+	f.Fuzz(func(t *testing.T, b []byte) {
+		for _, frame := range csafe.ParseFrames(log, b) {
+			_, _ = parseResponses(log, frame)
+		}
+	})
+}
+
+// FuzzParsers feeds arbitrary data to every response parser, both directly and wrapped.
+func FuzzParsers(f *testing.F) {
+	f.Add(byte(csafe_GETID_CMD), []byte{0x30, 0x30, 0x30, 0x30, 0x30})
+	f.Add(byte(csafe_GETPOWER_CMD), []byte{0x07, 0x00, 0x58})
+	f.Add(byte(csafe_PM_GET_WORKOUTSTATE), []byte{0x01})
+	f.Add(byte(csafe_PM_GET_STROKESTATS), make([]byte, 16))
+	f.Add(byte(csafe_SETUSERCFG1_CMD), []byte{0x8D, 0x01, 0x01})
+	f.Add(byte(csafe_SETUSERCFG1_CMD), []byte{0x8D, 0x05, 0x01})
+
+	log := slog.New(slog.DiscardHandler)
+	f.Fuzz(func(t *testing.T, cmd byte, data []byte) {
+		if parser, ok := parserMap[cmd]; ok {
+			_, _ = parser(data)
+		}
+
+		frame := csafe.ExtendedResponseFrame{
+			CommandResponses: []csafe.Response{{Command: cmd, DataByteCount: byte(len(data)), Data: data}},
+		}
+		_, _ = parseResponses(log, frame)
+	})
+}
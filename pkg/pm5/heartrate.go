@@ -0,0 +1,103 @@
+package pm5
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// HeartRate is a heart rate reading taken by HeartRateStream.
+type HeartRate struct {
+	Time           time.Time       // When the reading was taken, for lining it up with stroke data
+	Connected      bool            // Whether the PM is receiving data from a heart rate belt
+	BeatsPerMinute int             // Zero when no belt is connected
+	BeatIntervals  []time.Duration // Beat-to-beat intervals received since the previous reading, oldest first
+}
+
+// HeartRateStream polls the PM for the heart rate every interval and emits a HeartRate per poll. While a belt is
+// connected it also reads the heartbeat data and turns the beat times into beat-to-beat intervals. Failed queries
+// are logged and polling continues. The channel is closed when ctx is done.
+//
+// The responses to the queries are emitted on the event stream as usual.
+func HeartRateStream(ctx context.Context, p *PM5, interval time.Duration) <-chan HeartRate {
+	out := make(chan HeartRate)
+	go func() {
+		defer close(out)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var tracker heartRateTracker
+		for {
+			if hr, err := pollHeartRate(ctx, p, &tracker); err != nil {
+				if ctx.Err() == nil {
+					p.log.Warn("heart rate poll failed", slog.Any("error", err))
+				}
+			} else {
+				select {
+				case out <- hr:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+func pollHeartRate(ctx context.Context, p *PM5, tracker *heartRateTracker) (HeartRate, error) {
+	now := time.Now()
+
+	hr, err := Query[GetHRCurResponse](ctx, p, GetHRCur())
+	if err != nil {
+		return HeartRate{}, err
+	}
+
+	var beats GetHeartbeatDataResponse
+	if hr.Connected() {
+		beats, err = Query[GetHeartbeatDataResponse](ctx, p, GetHeartbeatData())
+		if err != nil {
+			return HeartRate{}, err
+		}
+	}
+
+	reading := tracker.update(hr, beats)
+	reading.Time = now
+	return reading, nil
+}
+
+// heartRateTracker turns successive heart rate responses into HeartRate readings. It remembers the last beat time so
+// that intervals can be computed across blocks of heartbeat data.
+type heartRateTracker struct {
+	lastBeat int
+	haveBeat bool
+}
+
+func (t *heartRateTracker) update(hr GetHRCurResponse, beats GetHeartbeatDataResponse) HeartRate {
+	if !hr.Connected() {
+		// The beat times of a belt paired later are unrelated to the ones seen so far.
+		t.haveBeat = false
+		return HeartRate{}
+	}
+
+	reading := HeartRate{Connected: true, BeatsPerMinute: hr.BeatsPerMinute}
+	for _, beat := range beats.BeatTimes {
+		if t.haveBeat {
+			if beat == t.lastBeat {
+				continue
+			}
+			ticks := uint16(beat - t.lastBeat) // The beat time wraps around every 64 s
+			reading.BeatIntervals = append(reading.BeatIntervals, time.Duration(ticks)*time.Second/1024)
+		}
+		t.lastBeat, t.haveBeat = beat, true
+	}
+
+	return reading
+}
@@ -0,0 +1,103 @@
+package pm5
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGetHRCurResponseConnected(t *testing.T) {
+	tests := []struct {
+		data []byte
+		want bool
+	}{
+		{data: []byte{0x00}, want: false},
+		{data: []byte{0xFF}, want: false},
+		{data: []byte{0x48}, want: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseGetHRCurResponse(tt.data)
+		if err != nil {
+			t.Fatalf("parse failed: %v", err)
+		}
+		if got.Connected() != tt.want {
+			t.Errorf("Connected() for %d bpm = %v, want %v", got.BeatsPerMinute, got.Connected(), tt.want)
+		}
+	}
+}
+
+func TestHeartRateTracker(t *testing.T) {
+	connected := GetHRCurResponse{BeatsPerMinute: 60}
+	beats := func(times ...int) GetHeartbeatDataResponse {
+		return GetHeartbeatDataResponse{BytesRead: 2 * len(times), BeatTimes: times}
+	}
+
+	steps := []struct {
+		name  string
+		hr    GetHRCurResponse
+		beats GetHeartbeatDataResponse
+		want  HeartRate
+	}{
+		{
+			name:  "first block",
+			hr:    connected,
+			beats: beats(1024, 2048, 2560),
+			want:  HeartRate{Connected: true, BeatsPerMinute: 60, BeatIntervals: []time.Duration{time.Second, 500 * time.Millisecond}},
+		},
+		{
+			name:  "repeated beat and wrap around",
+			hr:    connected,
+			beats: beats(2560, 65024, 512),
+			want:  HeartRate{Connected: true, BeatsPerMinute: 60, BeatIntervals: []time.Duration{61 * time.Second, time.Second}},
+		},
+		{
+			name: "no new beats",
+			hr:   connected,
+			want: HeartRate{Connected: true, BeatsPerMinute: 60},
+		},
+		{
+			name: "belt lost",
+			hr:   GetHRCurResponse{BeatsPerMinute: 255},
+			want: HeartRate{},
+		},
+		{
+			name:  "belt paired again",
+			hr:    connected,
+			beats: beats(100),
+			want:  HeartRate{Connected: true, BeatsPerMinute: 60},
+		},
+	}
+
+	var tracker heartRateTracker
+	for _, step := range steps {
+		if got := tracker.update(step.hr, step.beats); !reflect.DeepEqual(got, step.want) {
+			t.Errorf("%s:\ngot:  %+v\nwant: %+v", step.name, got, step.want)
+		}
+	}
+}
+
+func TestHeartRateStream(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// Every frame carries both the current heart rate (72 bpm) and a block of two beats one second apart.
+	contents := []byte{0xB0, 0x01, 0x48, 0x7F, 0x07, 0x6C, 0x05, 0x04, 0x00, 0x04, 0x00, 0x08}
+	p := respondingPM5(t, ctx,
+		responseReport(MachineStateInUse, contents...),
+		responseReport(0x80|MachineStateInUse, contents...),
+	)
+
+	select {
+	case hr := <-HeartRateStream(ctx, p, 10*time.Millisecond):
+		if !hr.Connected || hr.BeatsPerMinute != 72 || hr.Time.IsZero() {
+			t.Errorf("unexpected reading: %+v", hr)
+		}
+		if want := []time.Duration{time.Second}; !reflect.DeepEqual(hr.BeatIntervals, want) {
+			t.Errorf("intervals = %v, want %v", hr.BeatIntervals, want)
+		}
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for heart rate")
+	}
+}
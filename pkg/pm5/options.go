@@ -0,0 +1,123 @@
+package pm5
+
+import (
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/seagrayinc/gorow/internal/csafe"
+	"github.com/seagrayinc/gorow/internal/hid"
+)
+
+// Device is an opened HID device capable of report I/O. Implement it to run the PM5 protocol over an alternative
+// transport, or to inject a fake device in tests.
+type Device = hid.Device
+
+// Report is a single HID report exchanged with a Device.
+type Report = hid.Report
+
+// Channel is a byte stream to the PM used instead of a Device, such as the PM5 Bluetooth control characteristics
+// provided by package ble. Responses may arrive split into chunks; they are reassembled into frames.
+type Channel = csafe.Channel
+
+// Option configures a PM5 connection opened by Open.
+type Option func(*options)
+
+type options struct {
+	device      Device
+	channel     Channel
+	recording   io.Writer
+	path        string
+	serial      string
+	eventBuffer int
+	sendBuffer  int
+	sendPacing  time.Duration
+	large       bool
+	logger      *slog.Logger
+}
+
+func defaultOptions() options {
+	return options{
+		eventBuffer: 100,
+		sendBuffer:  100,
+		sendPacing:  50 * time.Millisecond,
+		logger:      slog.Default(),
+	}
+}
+
+// WithDevice uses an already opened device instead of looking up a PM5 over USB.
+func WithDevice(d Device) Option {
+	return func(o *options) {
+		o.device = d
+	}
+}
+
+// WithChannel exchanges CSAFE frames with the PM over ch instead of USB HID, e.g.
+//
+//	ch, err := ble.OpenControlChannel(ctx, gatt)
+//	p, err := pm5.Open(ctx, pm5.WithChannel(ch))
+func WithChannel(ch Channel) Option {
+	return func(o *options) {
+		o.channel = ch
+	}
+}
+
+// WithRecording captures every report exchanged with the PM to w, so the session can be reproduced later with a
+// Replayer. The capture is written as the reports are exchanged; w must be safe to use until the PM5 is closed.
+func WithRecording(w io.Writer) Option {
+	return func(o *options) {
+		o.recording = w
+	}
+}
+
+// WithDevicePath opens the HID device at the given OS-specific path, e.g. /dev/hidraw3 on Linux.
+func WithDevicePath(path string) Option {
+	return func(o *options) {
+		o.path = path
+	}
+}
+
+// WithSerial opens the PM5 with the given USB serial number. Useful when more than one PM5 is connected.
+func WithSerial(serial string) Option {
+	return func(o *options) {
+		o.serial = serial
+	}
+}
+
+// WithEventBuffer sets the number of events buffered by EventStream (default 100). When the buffer is full, the oldest
+// events are dropped.
+func WithEventBuffer(n int) Option {
+	return func(o *options) {
+		o.eventBuffer = n
+	}
+}
+
+// WithSendBuffer sets the number of commands buffered by Send (default 100).
+func WithSendBuffer(n int) Option {
+	return func(o *options) {
+		o.sendBuffer = n
+	}
+}
+
+// WithSendPacing sets the minimum time between frames when the PM has not yet responded to the previous one
+// (default 50ms).
+func WithSendPacing(d time.Duration) Option {
+	return func(o *options) {
+		o.sendPacing = d
+	}
+}
+
+// WithLargeReports lets bursts of commands that don't fit in the default 120 byte report be sent in a single 500 byte
+// report instead of several frames. Not every PM firmware answers large reports reliably.
+func WithLargeReports() Option {
+	return func(o *options) {
+		o.large = true
+	}
+}
+
+// WithLogger sets the logger used for protocol diagnostics (default slog.Default()).
+func WithLogger(l *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
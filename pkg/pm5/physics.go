@@ -0,0 +1,214 @@
+package pm5
+
+import (
+	"math"
+	"time"
+)
+
+// Concept2 indoor rower constants, as used by the PM to turn flywheel speed into distance.
+const (
+	flywheelInertia = 0.1001 // kg m^2
+	sprocketRadius  = 0.014  // m
+	minFlywheelRate = 20     // rad/s below which the PM waits for the wheel to reach its minimum speed
+	physicsStep     = time.Millisecond
+)
+
+// PhysicsRower models an athlete rowing on a Concept2 indoor rower. During the drive the athlete pulls the handle with
+// a force following a half sine, accelerating the flywheel against its air resistance; during the recovery the
+// flywheel spins down freely. Distance is derived from the flywheel speed the way the PM does it, so pace and power
+// follow the Concept2 relationship P = 2.80 / (t/d)^3.
+//
+// The athlete adjusts the force of every drive to converge on the target pace, starting from a flywheel at rest.
+type PhysicsRower struct {
+	Pace       time.Duration // Target pace per 500 m
+	StrokeRate int
+	Ratio      float64 // Recovery time over drive time
+	DragFactor int     // Drag factor of the flywheel, set by the damper
+
+	flywheel   float64       // Angular velocity in rad/s
+	peakForce  float64       // Peak force of the next drive, in N
+	phase      time.Duration // Time into the current stroke, starting with the drive
+	drive      physicsDrive
+	meters     float64 // Distance since the end of the previous drive
+	recovery   time.Duration
+	recoveryHi float64 // Flywheel speed at the start of the recovery
+	measuredDF int
+}
+
+// physicsDrive accumulates the current drive.
+type physicsDrive struct {
+	angle  float64 // Flywheel rotation in rad
+	work   float64 // J
+	peak   float64 // N
+	forces []float64
+}
+
+// NewPhysicsRower returns a PhysicsRower aiming for the given pace per 500 m and stroke rate, with a recovery twice as
+// long as the drive and a drag factor of 120.
+func NewPhysicsRower(pace time.Duration, strokeRate int) *PhysicsRower {
+	return &PhysicsRower{Pace: pace, StrokeRate: strokeRate, Ratio: 2, DragFactor: 120}
+}
+
+// FlywheelSpeed returns the angular velocity of the flywheel in rad/s.
+func (r *PhysicsRower) FlywheelSpeed() float64 {
+	return r.flywheel
+}
+
+// drag returns the drag constant k of the flywheel, where the drag torque is k ω².
+func (r *PhysicsRower) drag() float64 {
+	return float64(r.DragFactor) * 1e-6
+}
+
+// linearSpeed returns the boat speed in m/s matching a flywheel speed, so that k ω³ = 2.80 v³.
+func (r *PhysicsRower) linearSpeed(flywheel float64) float64 {
+	return math.Cbrt(r.drag()/wattsPerCubicSpeed) * flywheel
+}
+
+func (r *PhysicsRower) Row(dt time.Duration, rowing bool) RowerStep {
+	if r.measuredDF == 0 {
+		r.measuredDF = r.DragFactor
+	}
+	if !rowing || r.Pace <= 0 || r.StrokeRate <= 0 {
+		r.phase, r.meters, r.recovery = 0, 0, 0
+		meters, watts := r.coast(dt)
+		state := StrokeStateRecovery
+		if r.flywheel < minFlywheelRate {
+			state = StrokeStateWaitingForWheelToReachMinSpeed
+		}
+		return RowerStep{StrokeState: state, Meters: meters, Watts: watts, DragFactor: r.measuredDF}
+	}
+
+	cycle := time.Minute / time.Duration(r.StrokeRate)
+	drive := time.Duration(float64(cycle) / (1 + r.Ratio))
+	if r.peakForce == 0 {
+		r.peakForce = r.initialForce(cycle, drive)
+	}
+
+	step := RowerStep{StrokeState: StrokeStateRecovery}
+	var joules float64
+	for elapsed := time.Duration(0); elapsed < dt; elapsed += physicsStep {
+		h := min(physicsStep, dt-elapsed)
+		if r.phase < drive {
+			if r.phase == 0 {
+				r.drive = physicsDrive{}
+			}
+			meters, watts := r.pull(r.phase, drive, h)
+			r.meters += meters
+			step.Meters += meters
+			joules += watts * h.Seconds()
+			r.phase += h
+			if r.phase >= drive {
+				step.Stroke, step.ForceCurve = r.stroke(r.phase)
+				r.recoveryHi = r.flywheel
+			}
+		} else {
+			meters, watts := r.coast(h)
+			r.meters += meters
+			r.recovery += h
+			step.Meters += meters
+			joules += watts * h.Seconds()
+			r.phase += h
+			if r.phase >= cycle {
+				r.measureDrag()
+				r.phase = 0
+			}
+		}
+	}
+	if r.phase > 0 && r.phase < drive {
+		step.StrokeState = StrokeStateDriving
+	}
+	step.Watts = joules / dt.Seconds()
+	step.DragFactor = r.measuredDF
+	return step
+}
+
+// initialForce estimates the peak force needed to row at the target pace once the flywheel is up to speed.
+func (r *PhysicsRower) initialForce(cycle, drive time.Duration) float64 {
+	watts := PaceWatts(r.Pace)
+	flywheel := math.Cbrt(watts / r.drag())
+	return watts * cycle.Seconds() * math.Pi / (2 * drive.Seconds() * sprocketRadius * flywheel)
+}
+
+// pull advances the drive by h, starting phase into a drive of the given length. It returns the distance covered and
+// the average power dissipated by the flywheel.
+func (r *PhysicsRower) pull(phase, drive, h time.Duration) (float64, float64) {
+	mid := phase + h/2
+	force := r.peakForce * math.Sin(math.Pi*mid.Seconds()/drive.Seconds())
+	w0 := r.flywheel
+	torque := force*sprocketRadius - r.drag()*w0*w0
+	r.flywheel = max(w0+torque/flywheelInertia*h.Seconds(), 0)
+
+	angle := (w0 + r.flywheel) / 2 * h.Seconds()
+	r.drive.angle += angle
+	r.drive.work += force * sprocketRadius * angle
+	r.drive.peak = max(r.drive.peak, force)
+	r.drive.forces = append(r.drive.forces, force)
+
+	w := (w0 + r.flywheel) / 2
+	return r.linearSpeed(w) * h.Seconds(), r.drag() * w * w * w
+}
+
+// coast lets the flywheel spin down freely for dt, following the exact solution of I dω/dt = -k ω². It returns the
+// distance covered and the average power dissipated.
+func (r *PhysicsRower) coast(dt time.Duration) (float64, float64) {
+	w0 := r.flywheel
+	if w0 <= 0 {
+		return 0, 0
+	}
+	k := r.drag()
+	r.flywheel = w0 / (1 + k*w0*dt.Seconds()/flywheelInertia)
+
+	angle := flywheelInertia / k * math.Log(w0/r.flywheel)
+	energy := flywheelInertia * (w0*w0 - r.flywheel*r.flywheel) / 2
+	return math.Cbrt(k/wattsPerCubicSpeed) * angle, energy / dt.Seconds()
+}
+
+// measureDrag computes the drag factor from the deceleration of the flywheel during the recovery that just ended,
+// the way the PM does: 1/ω grows linearly at k/I while the flywheel spins freely.
+func (r *PhysicsRower) measureDrag() {
+	t := r.recovery.Seconds()
+	if t <= 0 || r.recoveryHi <= 0 || r.flywheel <= 0 || r.flywheel >= r.recoveryHi {
+		return
+	}
+	k := flywheelInertia * (1/r.flywheel - 1/r.recoveryHi) / t
+	r.measuredDF = int(math.Round(k * 1e6))
+}
+
+// stroke returns the record and the force curve of the drive that just ended, and adjusts the force of the next drive
+// towards the target pace.
+func (r *PhysicsRower) stroke(drive time.Duration) (*Stroke, []int) {
+	d := r.drive
+	length := d.angle * sprocketRadius
+
+	s := &Stroke{
+		DriveTime:    drive,
+		RecoveryTime: r.recovery,
+		Length:       length,
+		Distance:     r.meters,
+		PeakForce:    d.peak / newtonsPerPound,
+	}
+	if length > 0 {
+		s.AverageForce = d.work / length / newtonsPerPound
+	}
+	if r.meters > 0 {
+		pace := time.Duration(float64(drive+r.recovery) * 500 / r.meters)
+		watts := PaceWatts(pace)
+		s.Watts = int(math.Round(watts))
+
+		// Only correct once a full stroke has been rowed; the first drive spins the flywheel up from rest. The
+		// correction is damped and bounded since part of the work goes into the flywheel while it speeds up.
+		if r.recovery > 0 {
+			estimate := r.initialForce(drive+r.recovery, drive)
+			r.peakForce = min(max(r.peakForce*math.Pow(PaceWatts(r.Pace)/watts, 1.0/6), estimate/2), estimate*1.5)
+		}
+	}
+
+	curve := make([]int, forceCurveSamples)
+	for i := range curve {
+		f := d.forces[(2*i+1)*len(d.forces)/(2*forceCurveSamples)]
+		curve[i] = int(math.Round(f / newtonsPerPound))
+	}
+
+	r.meters, r.recovery = 0, 0
+	return s, curve
+}
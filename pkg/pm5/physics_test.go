@@ -0,0 +1,130 @@
+package pm5
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+)
+
+// rowPhysics rows r for d in emulator ticks and returns the last step that ended a drive.
+func rowPhysics(r *PhysicsRower, d time.Duration) (last RowerStep) {
+	for range d / emulatorTick {
+		if s := r.Row(emulatorTick, true); s.Stroke != nil {
+			last = s
+		}
+	}
+	return last
+}
+
+func TestPhysicsRower(t *testing.T) {
+	tests := []struct {
+		name       string
+		pace       time.Duration
+		strokeRate int
+		dragFactor int
+	}{
+		{name: "steady state", pace: 2 * time.Minute, strokeRate: 24, dragFactor: 120},
+		{name: "light damper", pace: 2 * time.Minute, strokeRate: 24, dragFactor: 90},
+		{name: "race pace", pace: 95 * time.Second, strokeRate: 34, dragFactor: 130},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewPhysicsRower(tt.pace, tt.strokeRate)
+			r.DragFactor = tt.dragFactor
+
+			step := rowPhysics(r, 3*time.Minute)
+			s := step.Stroke
+			if s == nil {
+				t.Fatal("no stroke")
+			}
+
+			cycle := s.DriveTime + s.RecoveryTime
+			if rate := time.Minute.Seconds() / cycle.Seconds(); math.Abs(rate-float64(tt.strokeRate)) > 0.1 {
+				t.Errorf("stroke rate: got %.2f, want %d", rate, tt.strokeRate)
+			}
+			if ratio := s.RecoveryTime.Seconds() / s.DriveTime.Seconds(); math.Abs(ratio-2) > 0.02 {
+				t.Errorf("recovery to drive ratio: got %.2f, want 2", ratio)
+			}
+			if step.DragFactor != tt.dragFactor {
+				t.Errorf("drag factor: got %d, want %d", step.DragFactor, tt.dragFactor)
+			}
+
+			// The pace of the stroke converges on the target, and its power follows from the pace.
+			pace := time.Duration(float64(cycle) * 500 / s.Distance)
+			if (pace - tt.pace).Abs() > time.Second {
+				t.Errorf("pace: got %v, want %v", pace, tt.pace)
+			}
+			if want := int(math.Round(PaceWatts(pace))); s.Watts != want {
+				t.Errorf("watts: got %d, want %d", s.Watts, want)
+			}
+
+			// In steady state the work of the drive is what the flywheel dissipates over the stroke.
+			work := s.AverageForce * newtonsPerPound * s.Length
+			if dissipated := float64(s.Watts) * cycle.Seconds(); math.Abs(work-dissipated)/dissipated > 0.03 {
+				t.Errorf("drive work: got %.1f J, want %.1f J", work, dissipated)
+			}
+			if s.Length < 1 || s.Length > 1.8 {
+				t.Errorf("stroke length: got %.2f m", s.Length)
+			}
+
+			if len(step.ForceCurve) != forceCurveSamples {
+				t.Fatalf("force curve samples: got %d, want %d", len(step.ForceCurve), forceCurveSamples)
+			}
+			peak := 0
+			for _, f := range step.ForceCurve {
+				peak = max(peak, f)
+			}
+			if math.Abs(float64(peak)-s.PeakForce) > 1 {
+				t.Errorf("force curve peak: got %d, want %.0f", peak, s.PeakForce)
+			}
+		})
+	}
+}
+
+func TestPhysicsRowerSpinDown(t *testing.T) {
+	r := NewPhysicsRower(2*time.Minute, 24)
+	rowPhysics(r, time.Minute)
+
+	if s := r.Row(emulatorTick, false); s.StrokeState != StrokeStateRecovery || s.Meters <= 0 {
+		t.Errorf("first step after rowing: got %+v", s)
+	}
+	var step RowerStep
+	for range time.Minute / emulatorTick {
+		step = r.Row(emulatorTick, false)
+	}
+	if step.StrokeState != StrokeStateWaitingForWheelToReachMinSpeed {
+		t.Errorf("stroke state: got %d, want %d", step.StrokeState, StrokeStateWaitingForWheelToReachMinSpeed)
+	}
+}
+
+func TestEmulatorPhysicsRower(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	p, e := emulatedPM5(t, ctx, EmulatorConfig{Rower: NewPhysicsRower(110*time.Second, 28)})
+	e.Advance(3 * time.Minute)
+
+	responses, err := p.exchange(ctx, GetStrokeStats(), GetPower(), GetStroke500mPace(), GetDragFactor())
+	if err != nil {
+		t.Fatalf("exchange failed: %v", err)
+	}
+	stats, _ := find[GetStrokeStatsResponse](responses)
+	power, _ := find[GetPowerResponse](responses)
+	pace, _ := find[GetStroke500mPaceResponse](responses)
+	drag, _ := find[GetDragFactorResponse](responses)
+
+	if (pace.Per500m - 110*time.Second).Abs() > time.Second {
+		t.Errorf("pace: got %v, want 1m50s", pace.Per500m)
+	}
+	if want := PaceWatts(pace.Per500m); math.Abs(float64(power.StrokeWatts)-want) > 1 {
+		t.Errorf("power: got %d, want %.0f", power.StrokeWatts, want)
+	}
+	if drag.DragFactor != 120 {
+		t.Errorf("drag factor: got %d, want 120", drag.DragFactor)
+	}
+	if stats.DriveCounter < 80 || stats.StrokeLength < 100 || stats.StrokeDriveTime == 0 {
+		t.Errorf("stroke stats mismatch: %+v", stats)
+	}
+}
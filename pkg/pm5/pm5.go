@@ -5,7 +5,9 @@ package pm5
 
 import (
 	"context"
-	"time"
+	"errors"
+	"fmt"
+	"log/slog"
 
 	"github.com/seagrayinc/gorow/internal/csafe"
 	"github.com/seagrayinc/gorow/internal/hid"
@@ -28,51 +30,140 @@ type Command = csafe.Command
 
 // PM5 represents a connection to a Concept2 PM5 monitor over USB HID.
 type PM5 struct {
-	events    chan any
+	broker    *broker
+	events    <-chan any
 	transport csafe.Transport
+	log       *slog.Logger
 }
 
-// Open opens a connection to the PM5 monitor.
-func Open(ctx context.Context) (*PM5, error) {
+// Open opens a connection to the PM5 monitor. By default the first PM5 connected over USB is used; see the Option
+// functions for alternatives.
+func Open(ctx context.Context, opts ...Option) (*PM5, error) {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.channel != nil {
+		if o.recording != nil {
+			return nil, errors.New("pm5: recording is only supported for HID devices")
+		}
+		p := newConnection(ctx, o)
+		p.transport.Channel = o.channel
+		p.transport.StartSender(ctx)
+		p.publish(p.transport.PollChannel(ctx, o.channel.Notifications(ctx)))
+		return p, nil
+	}
+
+	dev := o.device
+	if dev == nil {
+		var err error
+		dev, err = openDevice(o)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if o.recording != nil {
+		dev = NewRecorder(dev, o.recording)
+	}
+
+	return newPM5(ctx, dev, o), nil
+}
+
+// openDevice opens the PM5 selected by the options.
+func openDevice(o options) (Device, error) {
 	mgr, err := hid.NewManager()
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("pm5: %w", err)
 	}
 
-	dev, err := mgr.OpenVIDPID(vidConcept2, pidPM5)
-	if err != nil {
-		panic(err)
+	switch {
+	case o.path != "":
+		dev, err := mgr.Open(o.path)
+		if err != nil {
+			return nil, fmt.Errorf("pm5: opening %s: %w", o.path, err)
+		}
+		return dev, nil
+
+	case o.serial != "":
+		devs, err := mgr.Enumerate(vidConcept2, pidPM5)
+		if err != nil {
+			return nil, fmt.Errorf("pm5: %w", err)
+		}
+		for _, d := range devs {
+			if d.SerialNumber == o.serial {
+				dev, err := mgr.Open(d.Path)
+				if err != nil {
+					return nil, fmt.Errorf("pm5: opening %s: %w", d.Path, err)
+				}
+				return dev, nil
+			}
+		}
+		return nil, fmt.Errorf("pm5: no PM5 with serial number %q connected", o.serial)
+
+	default:
+		dev, err := mgr.OpenVIDPID(vidConcept2, pidPM5)
+		if err != nil {
+			return nil, fmt.Errorf("pm5: %w", err)
+		}
+		return dev, nil
 	}
+}
+
+// newPM5 starts the send and receive loops for an already opened device.
+func newPM5(ctx context.Context, dev Device, o options) *PM5 {
+	p := newConnection(ctx, o)
+	p.transport.Device = dev
+	p.transport.StartSender(ctx)
+	p.publish(p.transport.Poll(ctx, dev.PollReports(ctx)))
+	return p
+}
 
+// newConnection returns a PM5 whose transport is not yet attached to a device or channel.
+func newConnection(ctx context.Context, o options) *PM5 {
 	p := &PM5{
-		events: make(chan any, 100),
+		broker: newBroker(),
 		transport: csafe.Transport{
-			Device:        dev,
 			ReportLengths: reportLengths,
-			SendTimeout:   50 * time.Millisecond,
-			SendBuffer:    100,
+			SendTimeout:   o.sendPacing,
+			SendBuffer:    o.sendBuffer,
+			LargeReports:  o.large,
+			Logger:        o.logger,
 		},
+		log: o.logger,
 	}
-	p.transport.StartSender(ctx)
+	p.events = subscribe[any](ctx, p.broker, subscribeOptions{buffer: o.eventBuffer, overflow: DropOldest})
+	p.transport.OnError = func(err error) {
+		p.broker.publish(err)
+	}
+	return p
+}
 
-	reports := dev.PollReports(ctx)
+// publish publishes the responses in the frames received until frames is closed.
+func (p *PM5) publish(frames <-chan csafe.ExtendedResponseFrame) {
 	go func() {
-		for f := range p.transport.Poll(ctx, reports) {
-			parsed, err := parseResponses(f)
+		defer p.broker.close()
+
+		for f := range frames {
+			parsed, err := parseResponses(p.log, f)
 			if err != nil {
 				continue
 			}
 
 			for _, r := range parsed {
-				p.events <- r
+				p.broker.publish(r)
 			}
 		}
 	}()
+}
 
-	return p, nil
+// Close closes the underlying device.
+func (p *PM5) Close() error {
+	return p.transport.Close()
 }
 
-// EventStream returns a channel that emits PM5 events as they are received.
+// EventStream returns a channel that emits every PM5 event as it is received. Once its buffer is full, the oldest
+// events are dropped; use Subscribe for typed channels with a choice of overflow policy.
 func (p *PM5) EventStream() <-chan any {
 	return p.events
 }
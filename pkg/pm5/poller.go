@@ -0,0 +1,270 @@
+package pm5
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/seagrayinc/gorow/internal/csafe"
+)
+
+// Schedule declares which commands a Poller sends and when.
+type Schedule struct {
+	Rates           []Rate
+	StrokeTriggers  []StrokeTrigger
+	WorkoutTriggers []WorkoutStateTrigger
+}
+
+// Rate sends commands at a fixed interval.
+type Rate struct {
+	Interval time.Duration
+	Commands []Command
+}
+
+// StrokeEdge is a stroke state transition that fires a StrokeTrigger.
+type StrokeEdge int
+
+const (
+	// DriveStart fires when the stroke state changes to driving, i.e. at the end of the previous stroke's recovery.
+	DriveStart StrokeEdge = iota
+	// DriveEnd fires when the stroke state changes from driving to dwelling or recovery.
+	DriveEnd
+)
+
+// StrokeTrigger sends commands on a stroke edge. Edges are detected from the GetStrokeStateResponses the Poller
+// receives, so a Rate must poll GetStrokeState.
+type StrokeTrigger struct {
+	Edge     StrokeEdge
+	Commands []Command
+}
+
+// WorkoutStateTrigger sends commands when the workout state changes to State, one of the keys of WorkoutStateMap.
+// A Rate must poll GetWorkoutState.
+type WorkoutStateTrigger struct {
+	State    int
+	Commands []Command
+}
+
+// DefaultSchedule polls the stroke and workout state ten times a second, reads the stroke stats and power at the
+// start of every drive, and the remaining live metrics once a second.
+func DefaultSchedule() Schedule {
+	return Schedule{
+		Rates: []Rate{
+			{Interval: 100 * time.Millisecond, Commands: []Command{GetStrokeState(), GetWorkoutState()}},
+			{Interval: time.Second, Commands: []Command{
+				GetWorkTime(), GetWorkDistance(), GetStroke500mPace(), GetStrokeRate(), GetCalories(), GetHRCur(),
+				GetDragFactor(),
+			}},
+		},
+		StrokeTriggers: []StrokeTrigger{
+			{Edge: DriveStart, Commands: []Command{GetStrokeStats(), GetPower()}},
+		},
+	}
+}
+
+// Snapshot holds the latest value of every metric a Poller has received. Metrics that were never polled keep their
+// zero value.
+type Snapshot struct {
+	Time         time.Time // When the snapshot was last updated
+	Status       GetStatusResponse
+	StrokeState  int
+	WorkoutState int
+	WorkTime     time.Duration
+	Meters       float64
+	Pace         time.Duration // Time per 500 m of the last stroke
+	StrokeRate   int
+	Calories     int
+	Power        int // Watts
+	HeartRate    int // Zero without a heart rate belt
+	DragFactor   int
+	Strokes      int                    // Number of drives seen, from the stroke stats
+	LastStroke   GetStrokeStatsResponse // Stats of the last completed stroke
+}
+
+// Poller sends the commands of a Schedule and collects the responses into a Snapshot.
+//
+// The Poller waits for the PM's response to each frame before sending the next one, so it never queues commands
+// faster than the transport can deliver them: when the PM or the send pacing is slower than a Rate, the Rate is
+// stretched instead of building a backlog. Commands that fall due together are sent in one frame if they fit.
+type Poller struct {
+	p        *PM5
+	schedule Schedule
+
+	mu       sync.Mutex
+	snapshot Snapshot
+
+	haveStrokeState  bool
+	haveWorkoutState bool
+}
+
+// NewPoller returns a Poller for the schedule. Call Run to start polling.
+func NewPoller(p *PM5, schedule Schedule) *Poller {
+	return &Poller{p: p, schedule: schedule}
+}
+
+// Snapshot returns the latest metrics.
+func (pl *Poller) Snapshot() Snapshot {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	return pl.snapshot
+}
+
+// Run polls until ctx is done. Every updated Snapshot is also published as an event, so it can be consumed with
+// Subscribe[Snapshot]. Failed frames are logged and polling continues.
+func (pl *Poller) Run(ctx context.Context) error {
+	next := make([]time.Time, len(pl.schedule.Rates))
+	var triggered [][]Command
+
+	for {
+		now := time.Now()
+
+		due := triggered
+		triggered = nil
+		for i, r := range pl.schedule.Rates {
+			if !now.Before(next[i]) {
+				due = append(due, r.Commands)
+				next[i] = now.Add(r.Interval)
+			}
+		}
+
+		fired, err := pl.poll(ctx, now, due)
+		if err != nil {
+			return err
+		}
+		triggered = fired
+
+		// Triggered commands are sent right away; otherwise wait for the next Rate to fall due.
+		if len(triggered) > 0 {
+			continue
+		}
+
+		if len(next) == 0 {
+			// Nothing is scheduled at a fixed rate, so nothing can fire a trigger either.
+			<-ctx.Done()
+			return ctx.Err()
+		}
+
+		select {
+		case <-time.After(time.Until(slices.MinFunc(next, time.Time.Compare))):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// poll sends the due commands in one frame, or one frame per schedule entry if they don't fit together, and returns
+// the commands of the triggers the responses fire. It only fails when ctx is done.
+func (pl *Poller) poll(ctx context.Context, now time.Time, due [][]Command) ([][]Command, error) {
+	if len(due) == 0 {
+		return nil, nil
+	}
+
+	frames := [][]Command{slices.Concat(due...)}
+	var triggered [][]Command
+	for i := 0; i < len(frames); i++ {
+		responses, err := pl.p.exchange(ctx, frames[i]...)
+		switch {
+		case err == nil:
+		case ctx.Err() != nil:
+			return nil, ctx.Err()
+		case errors.Is(err, csafe.ErrFrameTooLarge) && len(frames) == 1 && len(due) > 1:
+			frames = append(frames, due...)
+			continue
+		default:
+			pl.p.log.Warn("poll failed", slog.Any("error", err))
+			continue
+		}
+
+		snapshot, fired := pl.update(now, responses)
+		triggered = append(triggered, fired...)
+		pl.p.broker.publish(snapshot)
+	}
+
+	return triggered, nil
+}
+
+// update folds the responses into the snapshot and returns the commands of the triggers they fire.
+func (pl *Poller) update(now time.Time, responses []any) (Snapshot, [][]Command) {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+
+	s := &pl.snapshot
+	s.Time = now
+
+	var fired [][]Command
+	for _, r := range responses {
+		switch resp := r.(type) {
+		case GetStatusResponse:
+			s.Status = resp
+		case GetStrokeStateResponse:
+			if pl.haveStrokeState {
+				fired = append(fired, pl.strokeTriggers(s.StrokeState, resp.StrokeState)...)
+			}
+			s.StrokeState, pl.haveStrokeState = resp.StrokeState, true
+		case GetWorkoutStateResponse:
+			if pl.haveWorkoutState && resp.WorkoutState != s.WorkoutState {
+				for _, t := range pl.schedule.WorkoutTriggers {
+					if t.State == resp.WorkoutState {
+						fired = append(fired, t.Commands)
+					}
+				}
+			}
+			s.WorkoutState, pl.haveWorkoutState = resp.WorkoutState, true
+		case GetWorkTimeResponse:
+			s.WorkTime = resp.WorkTime
+		case GetTWorkResponse:
+			s.WorkTime = resp.WorkTime
+		case GetWorkDistanceResponse:
+			s.Meters = resp.Meters
+		case GetHorizontalResponse:
+			s.Meters = resp.Meters
+		case GetStroke500mPaceResponse:
+			s.Pace = resp.Per500m
+		case GetPaceResponse:
+			s.Pace = resp.Per500m
+		case GetStrokeRateResponse:
+			s.StrokeRate = resp.StrokesPerMinute
+		case GetCadenceResponse:
+			s.StrokeRate = resp.StrokesPerMinute
+		case GetCaloriesResponse:
+			s.Calories = resp.Calories
+		case GetPowerResponse:
+			s.Power = resp.StrokeWatts
+		case GetHRCurResponse:
+			s.HeartRate = 0
+			if resp.Connected() {
+				s.HeartRate = resp.BeatsPerMinute
+			}
+		case GetDragFactorResponse:
+			s.DragFactor = resp.DragFactor
+		case GetStrokeStatsResponse:
+			s.LastStroke = resp
+			s.Strokes = resp.DriveCounter
+		}
+	}
+
+	return *s, fired
+}
+
+func (pl *Poller) strokeTriggers(from, to int) [][]Command {
+	var edge StrokeEdge
+	switch {
+	case from != StrokeStateDriving && to == StrokeStateDriving:
+		edge = DriveStart
+	case from == StrokeStateDriving && (to == StrokeStateDwellingAfterDrive || to == StrokeStateRecovery):
+		edge = DriveEnd
+	default:
+		return nil
+	}
+
+	var fired [][]Command
+	for _, t := range pl.schedule.StrokeTriggers {
+		if t.Edge == edge {
+			fired = append(fired, t.Commands)
+		}
+	}
+	return fired
+}
@@ -0,0 +1,123 @@
+package pm5
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPollerTriggers(t *testing.T) {
+	strokeStats := []Command{GetStrokeStats()}
+	drained := []Command{GetWorkTime()}
+	finished := []Command{GetWorkDistance()}
+
+	pl := NewPoller(nil, Schedule{
+		StrokeTriggers: []StrokeTrigger{
+			{Edge: DriveStart, Commands: strokeStats},
+			{Edge: DriveEnd, Commands: drained},
+		},
+		WorkoutTriggers: []WorkoutStateTrigger{
+			{State: 10, Commands: finished},
+		},
+	})
+
+	steps := []struct {
+		name      string
+		responses []any
+		want      [][]Command
+	}{
+		{
+			// The first states seen are not edges
+			name:      "initial state",
+			responses: []any{GetStrokeStateResponse{StrokeState: StrokeStateDriving}, GetWorkoutStateResponse{WorkoutState: 1}},
+		},
+		{
+			name:      "drive end",
+			responses: []any{GetStrokeStateResponse{StrokeState: StrokeStateRecovery}, GetWorkoutStateResponse{WorkoutState: 1}},
+			want:      [][]Command{drained},
+		},
+		{
+			name:      "recovery continues",
+			responses: []any{GetStrokeStateResponse{StrokeState: StrokeStateRecovery}},
+		},
+		{
+			name:      "drive start",
+			responses: []any{GetStrokeStateResponse{StrokeState: StrokeStateDriving}},
+			want:      [][]Command{strokeStats},
+		},
+		{
+			name:      "workout end",
+			responses: []any{GetWorkoutStateResponse{WorkoutState: 10}},
+			want:      [][]Command{finished},
+		},
+	}
+
+	for _, step := range steps {
+		if _, got := pl.update(time.Now(), step.responses); !reflect.DeepEqual(got, step.want) {
+			t.Errorf("%s: fired %v, want %v", step.name, got, step.want)
+		}
+	}
+}
+
+func TestPollerSnapshot(t *testing.T) {
+	pl := NewPoller(nil, Schedule{})
+
+	now := time.Now()
+	got, _ := pl.update(now, []any{
+		GetStatusResponse{StateMachineState: MachineStateInUse},
+		GetWorkTimeResponse{WorkTime: time.Minute},
+		GetWorkDistanceResponse{Meters: 250.5},
+		GetStroke500mPaceResponse{Per500m: 2 * time.Minute},
+		GetStrokeRateResponse{StrokesPerMinute: 24},
+		GetHRCurResponse{BeatsPerMinute: 255},
+		GetStrokeStatsResponse{DriveCounter: 12},
+	})
+
+	want := Snapshot{
+		Time:       now,
+		Status:     GetStatusResponse{StateMachineState: MachineStateInUse},
+		WorkTime:   time.Minute,
+		Meters:     250.5,
+		Pace:       2 * time.Minute,
+		StrokeRate: 24,
+		Strokes:    12,
+		LastStroke: GetStrokeStatsResponse{DriveCounter: 12},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("snapshot mismatch:\ngot:  %+v\nwant: %+v", got, want)
+	}
+	if !reflect.DeepEqual(pl.Snapshot(), want) {
+		t.Errorf("Snapshot() mismatch:\ngot:  %+v\nwant: %+v", pl.Snapshot(), want)
+	}
+}
+
+func TestPollerRun(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// The PM answers with a recovery, a drive, the stats of the finished stroke and another recovery, in turn.
+	p := respondingPM5(t, ctx,
+		responseReport(MachineStateInUse, 0x7F, 0x03, 0xBF, 0x01, StrokeStateRecovery),
+		responseReport(0x80|MachineStateInUse, 0x7F, 0x03, 0xBF, 0x01, StrokeStateDriving),
+		responseReport(MachineStateInUse,
+			0x7F, 0x12, 0x6E, 0x10, 0xB9, 0x00, 0x00, 0x2A, 0x00, 0x38, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08, 0x00,
+			0xB4, 0x03, 0x06, 0x00, 0x58,
+		),
+		responseReport(0x80|MachineStateInUse, 0x7F, 0x03, 0xBF, 0x01, StrokeStateRecovery),
+	)
+
+	snapshots := Subscribe[Snapshot](ctx, p)
+	pl := NewPoller(p, Schedule{
+		Rates:          []Rate{{Interval: 10 * time.Millisecond, Commands: []Command{GetStrokeState()}}},
+		StrokeTriggers: []StrokeTrigger{{Edge: DriveStart, Commands: []Command{GetStrokeStats(), GetPower()}}},
+	})
+	go pl.Run(ctx)
+
+	for s := range snapshots {
+		if s.Strokes == 2 && s.Power == 6 {
+			return
+		}
+	}
+	t.Fatal("timeout waiting for the triggered stroke stats")
+}
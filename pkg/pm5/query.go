@@ -0,0 +1,78 @@
+package pm5
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/seagrayinc/gorow/internal/csafe"
+)
+
+// DefaultQueryTimeout is applied by Query when the context has no deadline of its own.
+const DefaultQueryTimeout = time.Second
+
+var (
+	// ErrTimeout is returned by Query when no response frame arrives before the deadline.
+	ErrTimeout = errors.New("timed out waiting for response")
+
+	// ErrNoResponse is returned by Query when the response frame does not contain the expected command response.
+	ErrNoResponse = errors.New("response frame does not contain the expected command response")
+)
+
+// FrameStatusError reports that the PM rejected a frame, or kept reporting it bad or not ready after the transport
+// resent it. Query returns it as an error; for commands queued with Send it is emitted on the event stream.
+type FrameStatusError = csafe.FrameStatusError
+
+// Query sends a command in a frame of its own, waits for the PM's response to that frame and returns the decoded
+// response of type T, e.g.
+//
+//	stats, err := pm5.Query[pm5.GetStrokeStatsResponse](ctx, p, pm5.GetStrokeStats())
+//
+// The response is also emitted on the event stream as usual.
+func Query[T any](ctx context.Context, p *PM5, command Command) (T, error) {
+	var zero T
+
+	responses, err := p.exchange(ctx, command)
+	if err != nil {
+		return zero, fmt.Errorf("query %T: %w", zero, err)
+	}
+
+	if resp, ok := find[T](responses); ok {
+		return resp, nil
+	}
+
+	return zero, fmt.Errorf("query %T: %w", zero, ErrNoResponse)
+}
+
+// find returns the first response of type T.
+func find[T any](responses []any) (T, bool) {
+	for _, r := range responses {
+		if resp, ok := r.(T); ok {
+			return resp, true
+		}
+	}
+
+	var zero T
+	return zero, false
+}
+
+// exchange sends the commands in a frame of their own and returns the parsed responses, including the leading
+// GetStatusResponse.
+func (p *PM5) exchange(ctx context.Context, commands ...Command) ([]any, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultQueryTimeout)
+		defer cancel()
+	}
+
+	f, err := p.transport.Request(ctx, commands...)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, ErrTimeout
+		}
+		return nil, err
+	}
+
+	return parseResponses(p.log, f)
+}
@@ -0,0 +1,110 @@
+package pm5
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/seagrayinc/gorow/internal/csafe"
+	hid2 "github.com/seagrayinc/gorow/internal/hid"
+)
+
+// responseReport builds an unstuffed extended response frame from the PM to the host. The contents must not contain
+// any of the reserved frame flag bytes.
+func responseReport(status byte, contents ...byte) hid2.Report {
+	payload := append([]byte{status}, contents...)
+
+	data := []byte{csafe.ExtendedFrameStartFlag, csafe.ExtendedFrameAddressPCHostPrimary, csafe.ExtendedFrameAddressDefaultSecondary}
+	data = append(data, payload...)
+	data = append(data, csafe.Checksum(payload), csafe.StopFrameFlag)
+	return hid2.Report{ID: 0x02, Data: data}
+}
+
+// respondingPM5 returns a PM5 backed by a MockHID that answers the written reports with the given responses in turn,
+// starting over after the last one. Consecutive responses must alternate the frame toggle, or the transport drops
+// them as duplicates. Without responses the device never answers.
+func respondingPM5(t *testing.T, ctx context.Context, responses ...hid2.Report) *PM5 {
+	t.Helper()
+
+	mockHID := hid2.NewMockHID()
+	var writes atomic.Int64
+	mockHID.OnWrite = func(hid2.Report) {
+		if len(responses) > 0 {
+			n := writes.Add(1) - 1
+			go mockHID.Emit(responses[n%int64(len(responses))])
+		}
+	}
+	p, err := Open(ctx, WithDevice(mockHID))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	return p
+}
+
+func TestQuery(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// Wrapped CSAFE_PM_GET_WORKOUTSTATE response reporting "Workout row"
+	response := responseReport(0x01, 0x1A, 0x03, 0x8D, 0x01, 0x01)
+	p := respondingPM5(t, ctx, response)
+
+	got, err := Query[GetWorkoutStateResponse](ctx, p, GetWorkoutState())
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+
+	want := GetWorkoutStateResponse{WorkoutState: 1, WorkoutStateString: "Workout row"}
+	if got != want {
+		t.Errorf("response mismatch:\ngot:  %+v\nwant: %+v", got, want)
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	rejected := responseReport(FrameStatusReject|MachineStateReady, 0x1A, 0x03, 0x8D, 0x01, 0x01)
+	empty := responseReport(MachineStateReady)
+
+	tests := []struct {
+		name     string
+		response []hid2.Report
+		check    func(error) bool
+	}{
+		{
+			name:     "timeout",
+			response: nil,
+			check:    func(err error) bool { return errors.Is(err, ErrTimeout) },
+		},
+		{
+			name:     "rejected",
+			response: []hid2.Report{rejected},
+			check: func(err error) bool {
+				var statusErr *FrameStatusError
+				return errors.As(err, &statusErr) && statusErr.Status == FrameStatusReject
+			},
+		},
+		{
+			name:     "missing response",
+			response: []hid2.Report{empty},
+			check:    func(err error) bool { return errors.Is(err, ErrNoResponse) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			p := respondingPM5(t, ctx, tt.response...)
+
+			queryCtx, cancelQuery := context.WithTimeout(ctx, 200*time.Millisecond)
+			defer cancelQuery()
+
+			_, err := Query[GetWorkoutStateResponse](queryCtx, p, GetWorkoutState())
+			if !tt.check(err) {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package pm5
+
+import (
+	"math"
+	"time"
+)
+
+// Rower models the athlete on an Emulator. The emulator calls Row for every simulation step of a workout, with
+// rowing set during the work parts and cleared during rests and outside workouts.
+type Rower interface {
+	Row(dt time.Duration, rowing bool) RowerStep
+}
+
+// RowerStep is what happened during one simulation step of a Rower.
+type RowerStep struct {
+	StrokeState int     // One of the StrokeState constants
+	Meters      float64 // Distance covered during the step
+	Watts       float64 // Average power during the step
+	DragFactor  int
+	// Stroke is set when a drive ended during the step. Its Time, DriveCounter and Missed fields are filled in by the
+	// Emulator.
+	Stroke *Stroke
+	// ForceCurve holds the force samples of the drive that ended, in lbs.
+	ForceCurve []int
+}
+
+// Concept2 relationship between power and boat speed: P = 2.80 / (t/d)^3, with the pace t/d in seconds per meter.
+const wattsPerCubicSpeed = 2.80
+
+// PaceWatts returns the power needed to row at the given pace per 500 m, the way the PM converts between them.
+func PaceWatts(per500m time.Duration) float64 {
+	return wattsPerCubicSpeed / math.Pow(per500m.Seconds()/500, 3)
+}
+
+// WattsPace returns the pace per 500 m that the given power produces. It is the inverse of PaceWatts, and zero for no
+// power.
+func WattsPace(watts float64) time.Duration {
+	if watts <= 0 {
+		return 0
+	}
+	return time.Duration(500 * math.Cbrt(wattsPerCubicSpeed/watts) * float64(time.Second))
+}
+
+const newtonsPerPound = 4.4482216
+
+// forceCurveSamples is the number of force samples a simulated drive produces.
+const forceCurveSamples = 24
+
+// SteadyRower rows at a constant pace and stroke rate, with a drive taking a third of every stroke. The force of every
+// drive follows a half sine.
+type SteadyRower struct {
+	Pace       time.Duration // Per 500 m
+	StrokeRate int
+	Length     float64 // Stroke length in meters
+	DragFactor int
+
+	phase time.Duration // Time into the current stroke
+}
+
+// NewSteadyRower returns a SteadyRower at the given pace per 500 m and stroke rate, with a 1.4 m stroke and a drag
+// factor of 120.
+func NewSteadyRower(pace time.Duration, strokeRate int) *SteadyRower {
+	return &SteadyRower{Pace: pace, StrokeRate: strokeRate, Length: 1.4, DragFactor: 120}
+}
+
+func (r *SteadyRower) Row(dt time.Duration, rowing bool) RowerStep {
+	if !rowing || r.Pace <= 0 || r.StrokeRate <= 0 {
+		r.phase = 0
+		return RowerStep{StrokeState: StrokeStateWaitingForWheelToReachMinSpeed, DragFactor: r.DragFactor}
+	}
+
+	cycle := time.Minute / time.Duration(r.StrokeRate)
+	drive := cycle / 3
+	speed := 500 / r.Pace.Seconds()
+	watts := PaceWatts(r.Pace)
+
+	step := RowerStep{
+		StrokeState: StrokeStateDriving,
+		Meters:      speed * dt.Seconds(),
+		Watts:       watts,
+		DragFactor:  r.DragFactor,
+	}
+
+	before := r.phase
+	r.phase += dt
+	if before < drive && r.phase >= drive {
+		step.Stroke, step.ForceCurve = r.stroke(cycle, drive, speed, watts)
+	}
+	if r.phase >= drive {
+		step.StrokeState = StrokeStateRecovery
+	}
+	if r.phase >= cycle {
+		r.phase -= cycle
+	}
+
+	return step
+}
+
+// stroke returns the record and the force curve of a drive.
+func (r *SteadyRower) stroke(cycle, drive time.Duration, speed, watts float64) (*Stroke, []int) {
+	work := watts * cycle.Seconds()
+	average := work / r.Length / newtonsPerPound
+	peak := average * math.Pi / 2
+
+	curve := make([]int, forceCurveSamples)
+	for i := range curve {
+		curve[i] = int(math.Round(peak * math.Sin(math.Pi*(float64(i)+0.5)/forceCurveSamples)))
+	}
+
+	return &Stroke{
+		DriveTime:    drive,
+		RecoveryTime: cycle - drive,
+		Length:       r.Length,
+		Distance:     speed * cycle.Seconds(),
+		PeakForce:    peak,
+		AverageForce: average,
+		Watts:        int(math.Round(watts)),
+	}, curve
+}
@@ -0,0 +1,241 @@
+package pm5
+
+import (
+	"fmt"
+	"time"
+)
+
+// SessionEventType identifies a workout lifecycle event.
+type SessionEventType int
+
+const (
+	WorkoutStarted SessionEventType = iota + 1
+	IntervalStarted
+	RestStarted
+	IntervalEnded
+	WorkoutFinished
+	WorkoutTerminated
+	Rearmed
+)
+
+func (t SessionEventType) String() string {
+	switch t {
+	case WorkoutStarted:
+		return "WorkoutStarted"
+	case IntervalStarted:
+		return "IntervalStarted"
+	case RestStarted:
+		return "RestStarted"
+	case IntervalEnded:
+		return "IntervalEnded"
+	case WorkoutFinished:
+		return "WorkoutFinished"
+	case WorkoutTerminated:
+		return "WorkoutTerminated"
+	case Rearmed:
+		return "Rearmed"
+	default:
+		return fmt.Sprintf("SessionEventType(%d)", int(t))
+	}
+}
+
+// SessionMetrics are the cumulative metrics of a workout, as last reported by the PM.
+type SessionMetrics struct {
+	WorkTime time.Duration
+	Meters   float64
+	Calories int
+	Strokes  int
+}
+
+// SessionEvent is a workout lifecycle event emitted by a Session.
+type SessionEvent struct {
+	Type     SessionEventType
+	Time     time.Time
+	Elapsed  time.Duration // Time since the workout started
+	Interval int           // Number of the current interval, starting at 1; zero outside interval workouts
+	Metrics  SessionMetrics
+}
+
+// Session tracks the lifecycle of workouts from GetWorkoutStateResponse and GetStatusResponse events and turns the
+// state changes into SessionEvents. It also follows the work time, distance, calorie and stroke count responses, and
+// Poller snapshots, so that every event carries the cumulative metrics at that point.
+//
+// Workout states that were missed between two polls are inferred where possible, e.g. an interval that went straight
+// from work to rest still produces IntervalEnded before RestStarted.
+type Session struct {
+	metrics SessionMetrics
+
+	sawWorkoutState bool
+	lastState       int
+	inUseSince      time.Time // First status in use while no workout state has been seen
+
+	started   bool
+	ended     bool
+	startTime time.Time
+	interval  int
+	working   bool // In the work part of an interval
+	resting   bool
+}
+
+// NewSession returns a Session waiting for a workout to start.
+func NewSession() *Session {
+	return &Session{lastState: -1}
+}
+
+// Metrics returns the latest cumulative metrics.
+func (s *Session) Metrics() SessionMetrics {
+	return s.metrics
+}
+
+// Observe feeds an event received at the given time to the session and returns the lifecycle events it causes.
+// Events of other types are ignored, so the session can be fed straight from EventStream.
+func (s *Session) Observe(at time.Time, event any) []SessionEvent {
+	switch e := event.(type) {
+	case GetWorkoutStateResponse:
+		s.sawWorkoutState = true
+		if e.WorkoutState == s.lastState {
+			return nil
+		}
+		s.lastState = e.WorkoutState
+		return s.workoutState(at, e.WorkoutState)
+
+	case GetStatusResponse:
+		return s.machineState(at, e.StateMachineState)
+
+	case GetWorkTimeResponse:
+		s.metrics.WorkTime = e.WorkTime
+	case GetTWorkResponse:
+		s.metrics.WorkTime = e.WorkTime
+	case GetWorkDistanceResponse:
+		s.metrics.Meters = e.Meters
+	case GetHorizontalResponse:
+		s.metrics.Meters = e.Meters
+	case GetCaloriesResponse:
+		s.metrics.Calories = e.Calories
+	case GetStrokeStatsResponse:
+		s.metrics.Strokes = e.DriveCounter
+	case Snapshot:
+		s.metrics = SessionMetrics{
+			WorkTime: e.WorkTime,
+			Meters:   e.Meters,
+			Calories: e.Calories,
+			Strokes:  e.Strokes,
+		}
+	}
+
+	return nil
+}
+
+// workoutState handles a change of the workout state, one of the keys of WorkoutStateMap.
+func (s *Session) workoutState(at time.Time, state int) []SessionEvent {
+	var events []SessionEvent
+
+	switch state {
+	case 0, 13:
+		if s.started {
+			events = append(events, s.event(at, Rearmed))
+			s.reset()
+		}
+
+	case 1, 2, 3, 4, 5, 6, 7, 8, 9:
+		if s.ended {
+			// A new workout started without passing through wait to begin.
+			s.reset()
+		}
+		if !s.started {
+			s.started, s.startTime = true, at
+			events = append(events, s.event(at, WorkoutStarted))
+		}
+
+		switch state {
+		case 4, 5:
+			if !s.working {
+				s.interval++
+				s.working, s.resting = true, false
+				events = append(events, s.event(at, IntervalStarted))
+			}
+		case 8, 9:
+			events = append(events, s.endInterval(at)...)
+		case 3:
+			events = append(events, s.endInterval(at)...)
+			if !s.resting {
+				s.resting = true
+				events = append(events, s.event(at, RestStarted))
+			}
+		}
+
+	case 10, 12:
+		events = append(events, s.end(at, WorkoutFinished)...)
+
+	case 11:
+		events = append(events, s.end(at, WorkoutTerminated)...)
+	}
+
+	return events
+}
+
+// machineState starts and finishes workouts from the CSAFE state machine, for callers that don't poll the workout
+// state. The status comes first in every response frame, so a start is only reported once a second status in use
+// has arrived without a workout state alongside the first, and once a workout state has been seen it alone decides
+// how the workout ended.
+func (s *Session) machineState(at time.Time, state byte) []SessionEvent {
+	if state != MachineStateInUse {
+		s.inUseSince = time.Time{}
+	}
+
+	switch state {
+	case MachineStateInUse:
+		if s.sawWorkoutState || (s.started && !s.ended) {
+			return nil
+		}
+		if s.inUseSince.IsZero() {
+			s.inUseSince = at
+			return nil
+		}
+		s.reset()
+		s.started, s.startTime = true, s.inUseSince
+		return []SessionEvent{s.event(at, WorkoutStarted)}
+
+	case MachineStateFinish:
+		if s.sawWorkoutState {
+			return nil
+		}
+		return s.end(at, WorkoutFinished)
+	}
+
+	return nil
+}
+
+func (s *Session) endInterval(at time.Time) []SessionEvent {
+	if !s.working {
+		return nil
+	}
+	s.working = false
+	return []SessionEvent{s.event(at, IntervalEnded)}
+}
+
+func (s *Session) end(at time.Time, t SessionEventType) []SessionEvent {
+	if !s.started || s.ended {
+		return nil
+	}
+
+	events := s.endInterval(at)
+	s.ended, s.resting = true, false
+	return append(events, s.event(at, t))
+}
+
+func (s *Session) reset() {
+	s.started, s.ended, s.working, s.resting = false, false, false, false
+	s.interval = 0
+	s.startTime = time.Time{}
+}
+
+func (s *Session) event(at time.Time, t SessionEventType) SessionEvent {
+	return SessionEvent{
+		Type:     t,
+		Time:     at,
+		Elapsed:  at.Sub(s.startTime),
+		Interval: s.interval,
+		Metrics:  s.metrics,
+	}
+}
@@ -0,0 +1,180 @@
+package pm5
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// timedEvent is an event received ms milliseconds after the start of a test timeline.
+type timedEvent struct {
+	ms    int
+	event any
+}
+
+var timelineStart = time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+
+// at returns the time ms milliseconds after the start of a test timeline.
+func at(ms int) time.Time {
+	return timelineStart.Add(time.Duration(ms) * time.Millisecond)
+}
+
+// replay feeds the events to observe at their times and returns everything it emitted.
+func replay[T any](events []timedEvent, observe func(time.Time, any) []T) []T {
+	var got []T
+	for _, e := range events {
+		got = append(got, observe(at(e.ms), e.event)...)
+	}
+	return got
+}
+
+func TestSession(t *testing.T) {
+	state := func(ms, s int) timedEvent {
+		return timedEvent{ms, GetWorkoutStateResponse{WorkoutState: s}}
+	}
+	status := func(ms int, s byte) timedEvent {
+		return timedEvent{ms, GetStatusResponse{StateMachineState: s}}
+	}
+	meters := func(ms int, m float64) timedEvent {
+		return timedEvent{ms, GetWorkDistanceResponse{Meters: m}}
+	}
+	ev := func(typ SessionEventType, ms, startMs, interval int, m float64) SessionEvent {
+		return SessionEvent{
+			Type:     typ,
+			Time:     at(ms),
+			Elapsed:  time.Duration(ms-startMs) * time.Millisecond,
+			Interval: interval,
+			Metrics:  SessionMetrics{Meters: m},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		events []timedEvent
+		want   []SessionEvent
+	}{
+		{
+			name: "single piece",
+			events: []timedEvent{
+				state(0, 0),
+				state(100, 0),
+				state(1000, 1),
+				meters(5000, 250),
+				state(60000, 10),
+				state(60100, 12),
+			},
+			want: []SessionEvent{
+				ev(WorkoutStarted, 1000, 1000, 0, 0),
+				ev(WorkoutFinished, 60000, 1000, 0, 250),
+			},
+		},
+		{
+			name: "intervals",
+			events: []timedEvent{
+				state(0, 0),
+				state(1000, 4),
+				meters(30000, 500),
+				state(31000, 8),
+				state(31100, 3),
+				state(90000, 6),
+				state(91000, 5),
+				meters(120000, 1000),
+				state(121000, 9),
+				state(121100, 10),
+			},
+			want: []SessionEvent{
+				ev(WorkoutStarted, 1000, 1000, 0, 0),
+				ev(IntervalStarted, 1000, 1000, 1, 0),
+				ev(IntervalEnded, 31000, 1000, 1, 500),
+				ev(RestStarted, 31100, 1000, 1, 500),
+				ev(IntervalStarted, 91000, 1000, 2, 500),
+				ev(IntervalEnded, 121000, 1000, 2, 1000),
+				ev(WorkoutFinished, 121100, 1000, 2, 1000),
+			},
+		},
+		{
+			// Polling too slow to see the work time to rest transition
+			name: "missed states",
+			events: []timedEvent{
+				state(0, 4),
+				state(31000, 3),
+			},
+			want: []SessionEvent{
+				ev(WorkoutStarted, 0, 0, 0, 0),
+				ev(IntervalStarted, 0, 0, 1, 0),
+				ev(IntervalEnded, 31000, 0, 1, 0),
+				ev(RestStarted, 31000, 0, 1, 0),
+			},
+		},
+		{
+			name: "terminate and rearm",
+			events: []timedEvent{
+				state(0, 1),
+				meters(5000, 20),
+				state(10000, 11),
+				state(12000, 13),
+				state(13000, 0),
+				state(20000, 1),
+			},
+			want: []SessionEvent{
+				ev(WorkoutStarted, 0, 0, 0, 0),
+				ev(WorkoutTerminated, 10000, 0, 0, 20),
+				ev(Rearmed, 12000, 0, 0, 20),
+				ev(WorkoutStarted, 20000, 20000, 0, 20),
+			},
+		},
+		{
+			name: "machine state only",
+			events: []timedEvent{
+				status(0, MachineStateReady),
+				status(1000, MachineStateInUse),
+				status(1100, MachineStateInUse),
+				status(2000, MachineStateInUse),
+				status(60000, MachineStateFinish),
+			},
+			want: []SessionEvent{
+				ev(WorkoutStarted, 1100, 1000, 0, 0),
+				ev(WorkoutFinished, 60000, 1000, 0, 0),
+			},
+		},
+		{
+			// The status of a frame comes before its workout state
+			name: "machine state ignored with workout state",
+			events: []timedEvent{
+				status(0, MachineStateInUse),
+				state(0, 0),
+				status(100, MachineStateInUse),
+				state(100, 0),
+				status(1000, MachineStateInUse),
+				state(1000, 1),
+			},
+			want: []SessionEvent{
+				ev(WorkoutStarted, 1000, 1000, 0, 0),
+			},
+		},
+		{
+			name: "terminated in the frame of the finish status",
+			events: []timedEvent{
+				status(0, MachineStateInUse),
+				state(0, 1),
+				meters(5000, 20),
+				status(10000, MachineStateFinish),
+				state(10000, 11),
+			},
+			want: []SessionEvent{
+				ev(WorkoutStarted, 0, 0, 0, 0),
+				ev(WorkoutTerminated, 10000, 0, 0, 20),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := replay(tt.events, NewSession().Observe)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("events mismatch:\ngot:  %+v\nwant: %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package pm5
+
+import (
+	"time"
+)
+
+// Stroke is the record of a single stroke assembled by a StrokeDetector.
+type Stroke struct {
+	Time         time.Time // When the stats of the stroke were received
+	DriveCounter int
+	Missed       int // Strokes between the previous record and this one that were not seen
+	DriveTime    time.Duration
+	RecoveryTime time.Duration
+	Length       float64 // Meters
+	Distance     float64 // Meters travelled during the stroke
+	PeakForce    float64 // lbs
+	AverageForce float64 // lbs
+	Watts        int     // Zero if no power was received for the stroke
+}
+
+// StrokeDetector turns stroke state, stroke stats and power responses into one Stroke per stroke. Strokes are
+// counted by GetStrokeStatsResponse.DriveCounter rather than stroke state transitions, so no stroke is lost when
+// polling is too slow to see every drive; gaps in the counter are reported in Stroke.Missed.
+//
+// A stroke is complete once the power for it arrives, the next drive starts or the next stroke stats arrive. Call
+// Flush to get a stroke still waiting for its power.
+type StrokeDetector struct {
+	pending *Stroke
+
+	lastState int
+
+	lastCounter int
+	haveCounter bool
+}
+
+// NewStrokeDetector returns a StrokeDetector that has not seen any strokes yet.
+func NewStrokeDetector() *StrokeDetector {
+	return &StrokeDetector{lastState: -1}
+}
+
+// Observe feeds an event received at the given time to the detector and returns the strokes it completes. Events of
+// other types are ignored, so the detector can be fed straight from EventStream.
+func (d *StrokeDetector) Observe(at time.Time, event any) []Stroke {
+	switch e := event.(type) {
+	case GetStrokeStateResponse:
+		var done []Stroke
+		if e.StrokeState == StrokeStateDriving && d.lastState != StrokeStateDriving {
+			done = d.Flush()
+		}
+		d.lastState = e.StrokeState
+		return done
+
+	case GetStrokeStatsResponse:
+		if d.haveCounter && e.DriveCounter == d.lastCounter {
+			// Stats of a stroke already recorded
+			return nil
+		}
+
+		done := d.Flush()
+		if e.DriveCounter == 0 {
+			// No stroke has been taken yet in this workout
+			d.lastCounter, d.haveCounter = 0, true
+			return done
+		}
+
+		missed := 0
+		if d.haveCounter && e.DriveCounter > d.lastCounter {
+			missed = e.DriveCounter - d.lastCounter - 1
+		}
+		// A counter that went backwards means a new workout started.
+		d.lastCounter, d.haveCounter = e.DriveCounter, true
+
+		d.pending = &Stroke{
+			Time:         at,
+			DriveCounter: e.DriveCounter,
+			Missed:       missed,
+			DriveTime:    time.Duration(e.StrokeDriveTime) * 10 * time.Millisecond,
+			RecoveryTime: time.Duration(e.StrokeRecoveryTime) * 10 * time.Millisecond,
+			Length:       float64(e.StrokeLength) / 100,
+			Distance:     float64(e.StrokeDistance) / 100,
+			PeakForce:    float64(e.PeakDriveForce) / 10,
+			AverageForce: float64(e.AverageDriveForce) / 10,
+		}
+		return done
+
+	case GetPowerResponse:
+		if d.pending == nil {
+			return nil
+		}
+		d.pending.Watts = e.StrokeWatts
+		return d.Flush()
+	}
+
+	return nil
+}
+
+// Flush returns the stroke waiting for its power, if any.
+func (d *StrokeDetector) Flush() []Stroke {
+	if d.pending == nil {
+		return nil
+	}
+
+	s := *d.pending
+	d.pending = nil
+	return []Stroke{s}
+}
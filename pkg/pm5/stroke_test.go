@@ -0,0 +1,131 @@
+package pm5
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStrokeDetector(t *testing.T) {
+	state := func(ms, s int) timedEvent {
+		return timedEvent{ms, GetStrokeStateResponse{StrokeState: s}}
+	}
+	stats := func(ms, counter int) timedEvent {
+		return timedEvent{ms, GetStrokeStatsResponse{
+			StrokeDistance:     1050,
+			StrokeDriveTime:    80,
+			StrokeRecoveryTime: 170,
+			StrokeLength:       142,
+			DriveCounter:       counter,
+			PeakDriveForce:     2105,
+			AverageDriveForce:  1250,
+		}}
+	}
+	power := func(ms, watts int) timedEvent {
+		return timedEvent{ms, GetPowerResponse{StrokeWatts: watts, UnitsSpecifier: PowerUnitsWatts}}
+	}
+	stroke := func(ms, counter, missed, watts int) Stroke {
+		return Stroke{
+			Time:         at(ms),
+			DriveCounter: counter,
+			Missed:       missed,
+			DriveTime:    800 * time.Millisecond,
+			RecoveryTime: 1700 * time.Millisecond,
+			Length:       1.42,
+			Distance:     10.5,
+			PeakForce:    210.5,
+			AverageForce: 125,
+			Watts:        watts,
+		}
+	}
+
+	tests := []struct {
+		name   string
+		events []timedEvent
+		want   []Stroke
+	}{
+		{
+			// Polled like examples/basic_polling_loop: stats and power are requested when a drive starts
+			name: "stats and power on drive start",
+			events: []timedEvent{
+				state(0, StrokeStateWaitingForWheelToAccelerate),
+				state(100, StrokeStateDriving),
+				stats(130, 0),
+				power(130, 0),
+				state(1000, StrokeStateRecovery),
+				state(2500, StrokeStateDriving),
+				stats(2530, 1),
+				power(2530, 180),
+				state(3400, StrokeStateDwellingAfterDrive),
+				state(3500, StrokeStateRecovery),
+				state(5000, StrokeStateDriving),
+				stats(5030, 2),
+				power(5030, 190),
+			},
+			want: []Stroke{stroke(2530, 1, 0, 180), stroke(5030, 2, 0, 190)},
+		},
+		{
+			// Polling too slow to see the recovery of stroke 2, and the stats of stroke 3 and 4 never requested
+			name: "slow polling",
+			events: []timedEvent{
+				state(0, StrokeStateDriving),
+				stats(30, 1),
+				power(30, 180),
+				state(2500, StrokeStateDriving),
+				stats(2530, 2),
+				power(2530, 185),
+				state(9000, StrokeStateRecovery),
+				stats(9030, 5),
+				power(9030, 200),
+			},
+			want: []Stroke{stroke(30, 1, 0, 180), stroke(2530, 2, 0, 185), stroke(9030, 5, 2, 200)},
+		},
+		{
+			name: "repeated stats",
+			events: []timedEvent{
+				stats(0, 7),
+				power(0, 150),
+				stats(500, 7),
+				power(500, 155),
+				stats(1000, 8),
+				power(1000, 160),
+			},
+			want: []Stroke{stroke(0, 7, 0, 150), stroke(1000, 8, 0, 160)},
+		},
+		{
+			// Without power, a stroke completes when the next drive starts or the next stats arrive
+			name: "no power",
+			events: []timedEvent{
+				stats(0, 1),
+				state(100, StrokeStateRecovery),
+				state(1500, StrokeStateDriving),
+				stats(1600, 2),
+				stats(4000, 3),
+			},
+			want: []Stroke{stroke(0, 1, 0, 0), stroke(1600, 2, 0, 0), stroke(4000, 3, 0, 0)},
+		},
+		{
+			name: "new workout",
+			events: []timedEvent{
+				stats(0, 41),
+				power(0, 200),
+				stats(60000, 0),
+				stats(62000, 1),
+				power(62000, 120),
+			},
+			want: []Stroke{stroke(0, 41, 0, 200), stroke(62000, 1, 0, 120)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewStrokeDetector()
+
+			got := append(replay(tt.events, d.Observe), d.Flush()...)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("strokes mismatch:\ngot:  %+v\nwant: %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package pm5
+
+import (
+	"context"
+	"sync"
+)
+
+// OverflowPolicy decides what a subscription does with a new event when its buffer is full.
+type OverflowPolicy int
+
+const (
+	// DropOldest discards the oldest buffered event to make room for the new one.
+	DropOldest OverflowPolicy = iota
+	// DropNewest discards the new event.
+	DropNewest
+	// Block waits until the subscriber makes room. A blocked subscriber holds up every other subscriber, so only use
+	// it for consumers that keep up.
+	Block
+)
+
+// SubscribeOption configures a subscription created by Subscribe.
+type SubscribeOption func(*subscribeOptions)
+
+type subscribeOptions struct {
+	buffer   int
+	overflow OverflowPolicy
+}
+
+func defaultSubscribeOptions() subscribeOptions {
+	return subscribeOptions{
+		buffer:   100,
+		overflow: DropOldest,
+	}
+}
+
+// WithBuffer sets the number of events buffered for the subscriber (default 100).
+func WithBuffer(n int) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.buffer = n
+	}
+}
+
+// WithOverflow sets the policy applied when the subscriber's buffer is full (default DropOldest).
+func WithOverflow(policy OverflowPolicy) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.overflow = policy
+	}
+}
+
+// Subscribe returns a channel that receives every event of type T, e.g.
+//
+//	for stats := range pm5.Subscribe[pm5.GetStrokeStatsResponse](ctx, p) {
+//		fmt.Println(stats.DriveCounter)
+//	}
+//
+// Every subscriber has its own buffer, so any number of them can consume the same events. Errors reported by the
+// transport can be subscribed to as error. The channel is closed when ctx is done or the PM5 connection ends.
+func Subscribe[T any](ctx context.Context, p *PM5, opts ...SubscribeOption) <-chan T {
+	o := defaultSubscribeOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return subscribe[T](ctx, p.broker, o)
+}
+
+// broker fans events out to the subscriptions.
+type broker struct {
+	mu     sync.Mutex
+	subs   map[*subscription]struct{}
+	closed bool
+
+	done      chan struct{} // Closed by close, unblocks publishes to subscriptions with the Block policy
+	closeOnce sync.Once
+}
+
+type subscription struct {
+	deliver func(event any)
+	close   func()
+}
+
+func newBroker() *broker {
+	return &broker{
+		subs: make(map[*subscription]struct{}),
+		done: make(chan struct{}),
+	}
+}
+
+// publish delivers the event to every subscription accepting its type.
+func (b *broker) publish(event any) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for s := range b.subs {
+		s.deliver(event)
+	}
+}
+
+// close ends all subscriptions; later subscriptions are closed right away.
+func (b *broker) close() {
+	b.closeOnce.Do(func() {
+		close(b.done)
+	})
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for s := range b.subs {
+		delete(b.subs, s)
+		s.close()
+	}
+}
+
+func (b *broker) add(s *subscription) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return false
+	}
+	b.subs[s] = struct{}{}
+	return true
+}
+
+func (b *broker) remove(s *subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subs[s]; ok {
+		delete(b.subs, s)
+		s.close()
+	}
+}
+
+func subscribe[T any](ctx context.Context, b *broker, o subscribeOptions) <-chan T {
+	ch := make(chan T, max(o.buffer, 0))
+
+	s := &subscription{
+		deliver: func(event any) {
+			if v, ok := event.(T); ok {
+				send(ctx, b.done, ch, v, o.overflow)
+			}
+		},
+		close: func() {
+			close(ch)
+		},
+	}
+
+	if !b.add(s) {
+		close(ch)
+		return ch
+	}
+	context.AfterFunc(ctx, func() {
+		b.remove(s)
+	})
+
+	return ch
+}
+
+// send delivers v to ch according to the overflow policy. It is only called with the broker lock held, which keeps
+// ch from being closed concurrently.
+func send[T any](ctx context.Context, done <-chan struct{}, ch chan T, v T, policy OverflowPolicy) {
+	switch policy {
+	case Block:
+		select {
+		case ch <- v:
+		case <-ctx.Done():
+		case <-done:
+		}
+
+	case DropNewest:
+		select {
+		case ch <- v:
+		default:
+		}
+
+	default:
+		select {
+		case ch <- v:
+			return
+		default:
+		}
+
+		// Make room and try again. Without a buffer, the event is dropped unless a receiver is waiting.
+		select {
+		case <-ch:
+		default:
+		}
+		select {
+		case ch <- v:
+		default:
+		}
+	}
+}
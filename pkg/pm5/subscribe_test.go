@@ -0,0 +1,179 @@
+package pm5
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// drain returns the events buffered in ch without waiting for more.
+func drain[T any](ch <-chan T) []T {
+	var events []T
+	for {
+		select {
+		case v, ok := <-ch:
+			if !ok {
+				return events
+			}
+			events = append(events, v)
+		default:
+			return events
+		}
+	}
+}
+
+func TestSubscribeFanOut(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	b := newBroker()
+	first := subscribe[GetPowerResponse](ctx, b, defaultSubscribeOptions())
+	second := subscribe[GetPowerResponse](ctx, b, defaultSubscribeOptions())
+	other := subscribe[GetStrokeStateResponse](ctx, b, defaultSubscribeOptions())
+	all := subscribe[any](ctx, b, defaultSubscribeOptions())
+
+	b.publish(GetPowerResponse{StrokeWatts: 200})
+	b.publish(GetStrokeStateResponse{StrokeState: StrokeStateDriving})
+	b.publish(GetPowerResponse{StrokeWatts: 210})
+
+	wantPower := []GetPowerResponse{{StrokeWatts: 200}, {StrokeWatts: 210}}
+	for name, ch := range map[string]<-chan GetPowerResponse{"first": first, "second": second} {
+		if got := drain(ch); !reflect.DeepEqual(got, wantPower) {
+			t.Errorf("%s subscriber got %+v, want %+v", name, got, wantPower)
+		}
+	}
+	if got := drain(other); len(got) != 1 {
+		t.Errorf("stroke state subscriber got %+v, want one event", got)
+	}
+	if got := drain(all); len(got) != 3 {
+		t.Errorf("any subscriber got %d events, want 3", len(got))
+	}
+}
+
+func TestSubscribeOverflow(t *testing.T) {
+	tests := []struct {
+		policy OverflowPolicy
+		want   []int
+	}{
+		{policy: DropOldest, want: []int{3, 4}},
+		{policy: DropNewest, want: []int{1, 2}},
+	}
+
+	for _, tt := range tests {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		b := newBroker()
+		ch := subscribe[int](ctx, b, subscribeOptions{buffer: 2, overflow: tt.policy})
+		for i := 1; i <= 4; i++ {
+			b.publish(i)
+		}
+
+		if got := drain(ch); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("policy %d: got %v, want %v", tt.policy, got, tt.want)
+		}
+	}
+}
+
+func TestSubscribeBlock(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	b := newBroker()
+	ch := subscribe[int](ctx, b, subscribeOptions{buffer: 1, overflow: Block})
+
+	published := make(chan struct{})
+	go func() {
+		defer close(published)
+		for i := 1; i <= 3; i++ {
+			b.publish(i)
+		}
+	}()
+
+	var got []int
+	for range 3 {
+		select {
+		case v := <-ch:
+			got = append(got, v)
+		case <-time.After(time.Second):
+			t.Fatal("timeout waiting for event")
+		}
+	}
+	<-published
+
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestSubscribeClose(t *testing.T) {
+	t.Run("context done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		b := newBroker()
+		ch := subscribe[int](ctx, b, subscribeOptions{buffer: 0, overflow: Block})
+
+		// A publish blocked on the subscriber is released when the subscription ends.
+		published := make(chan struct{})
+		go func() {
+			defer close(published)
+			b.publish(1)
+		}()
+		cancel()
+
+		waitClosed(t, ch)
+		<-published
+	})
+
+	t.Run("broker closed", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		b := newBroker()
+		ch := subscribe[int](ctx, b, defaultSubscribeOptions())
+		b.close()
+		waitClosed(t, ch)
+
+		waitClosed(t, subscribe[int](ctx, b, defaultSubscribeOptions()))
+	})
+}
+
+func waitClosed[T any](t *testing.T, ch <-chan T) {
+	t.Helper()
+
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-ch:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("timeout waiting for the subscription to close")
+		}
+	}
+}
+
+func TestSubscribeErrors(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	p := respondingPM5(t, ctx, responseReport(FrameStatusReject|MachineStateReady))
+	errs := Subscribe[error](ctx, p)
+
+	if err := p.Send(ctx, GetPower()); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	select {
+	case err := <-errs:
+		var statusErr *FrameStatusError
+		if !errors.As(err, &statusErr) {
+			t.Errorf("expected a FrameStatusError, got %v", err)
+		}
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for error")
+	}
+}
@@ -0,0 +1,179 @@
+package pm5
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/seagrayinc/gorow/internal/csafe"
+)
+
+// ErrIntervalNotRecorded is returned by IntervalRecorder.Summary when an interval ended without the recorder reading
+// its results.
+var ErrIntervalNotRecorded = errors.New("interval was not recorded during the workout")
+
+// WorkoutSummary is the split or interval history of a finished workout.
+type WorkoutSummary struct {
+	WorkoutType WorkoutType
+	Intervals   []IntervalSummary
+}
+
+// IntervalSummary holds the results of a single interval, or of a split of a single-piece workout.
+type IntervalSummary struct {
+	Time       time.Duration
+	Meters     int
+	Pace       time.Duration // Average time per 500 m
+	StrokeRate int           // Average strokes per minute
+	HeartRate  int           // Average beats per minute, zero without a heart rate belt
+	Rest       time.Duration
+}
+
+// FetchWorkoutSummary reads what the PM still reports of a workout once it has ended, i.e. GetWorkoutStateResponse
+// reports "Workout end": the workout type and the results of the last split or interval.
+//
+// The PM keeps only the last split: its "last split" commands report the split or interval that ended most recently,
+// and earlier ones cannot be read over CSAFE. Intervals therefore holds the last one alone. To keep every interval,
+// read each as it ends with an IntervalRecorder, or use the end of workout summary of package ble.
+func FetchWorkoutSummary(ctx context.Context, p *PM5) (WorkoutSummary, error) {
+	summary, _, err := fetchLastSplit(ctx, p)
+	if err != nil {
+		return WorkoutSummary{}, fmt.Errorf("fetch workout summary: %w", err)
+	}
+	return summary, nil
+}
+
+// fetchLastSplit reads the workout type and the results of the split or interval that ended last, and returns the
+// interval count.
+func fetchLastSplit(ctx context.Context, p *PM5) (WorkoutSummary, int, error) {
+	responses, err := p.exchange(ctx,
+		getPMCfg(
+			csafe.ShortCommand(csafe_PM_GET_WORKOUTTYPE),
+			csafe.ShortCommand(csafe_PM_GET_WORKOUTINTERVALCOUNT),
+		),
+		getLastSplit(),
+	)
+	if err != nil {
+		return WorkoutSummary{}, 0, err
+	}
+
+	workoutType, ok1 := find[GetWorkoutTypeResponse](responses)
+	count, ok2 := find[GetWorkoutIntervalCountResponse](responses)
+	interval, ok3 := lastSplit(responses)
+	if !ok1 || !ok2 || !ok3 {
+		return WorkoutSummary{}, 0, ErrNoResponse
+	}
+
+	summary := WorkoutSummary{WorkoutType: workoutType.WorkoutType, Intervals: []IntervalSummary{interval}}
+	return summary, count.IntervalCount, nil
+}
+
+// getLastSplit reads the results of the split or interval that ended last.
+func getLastSplit() Command {
+	return getPMData(
+		csafe.ShortCommand(csafe_PM_GET_LASTSPLITTIME),
+		csafe.ShortCommand(csafe_PM_GET_LASTSPLITDISTANCE),
+		csafe.ShortCommand(csafe_PM_GET_SPLITAVG500MPACE),
+		csafe.ShortCommand(csafe_PM_GET_SPLITAVGSTROKERATE),
+		csafe.ShortCommand(csafe_PM_GET_AVGHEARTRATE),
+		csafe.ShortCommand(csafe_PM_GET_RESTTIME),
+	)
+}
+
+// lastSplit returns the results read by getLastSplit.
+func lastSplit(responses []any) (IntervalSummary, bool) {
+	splitTime, ok1 := find[GetLastSplitTimeResponse](responses)
+	distance, ok2 := find[GetLastSplitDistanceResponse](responses)
+	pace, ok3 := find[GetSplitAvg500mPaceResponse](responses)
+	strokeRate, ok4 := find[GetSplitAvgStrokeRateResponse](responses)
+	heartRate, ok5 := find[GetAvgHeartRateResponse](responses)
+	rest, ok6 := find[GetRestTimeResponse](responses)
+	if !ok1 || !ok2 || !ok3 || !ok4 || !ok5 || !ok6 {
+		return IntervalSummary{}, false
+	}
+
+	return IntervalSummary{
+		Time:       splitTime.SplitTime,
+		Meters:     distance.Meters,
+		Pace:       pace.Per500m,
+		StrokeRate: strokeRate.StrokesPerMinute,
+		HeartRate:  heartRate.BeatsPerMinute,
+		Rest:       rest.RestTime,
+	}, true
+}
+
+// IntervalRecorder keeps the results of every interval of a workout. The PM only reports the interval that ended last,
+// so the recorder reads each one as it ends. Feed it the events of a Session:
+//
+//	session := pm5.NewSession()
+//	recorder := pm5.NewIntervalRecorder(p)
+//	for r := range p.EventStream() {
+//		for _, e := range session.Observe(time.Now(), r) {
+//			recorder.Observe(ctx, e)
+//		}
+//	}
+//	summary, err := recorder.Summary(ctx) // Once the workout has ended
+//
+// The Session only sees an interval end when the workout state changes, i.e. when the interval is followed by a rest
+// or ends the workout.
+type IntervalRecorder struct {
+	p *PM5
+
+	mu        sync.Mutex
+	intervals map[int]IntervalSummary // By zero-based interval number
+}
+
+// NewIntervalRecorder returns an IntervalRecorder reading the intervals from p.
+func NewIntervalRecorder(p *PM5) *IntervalRecorder {
+	return &IntervalRecorder{p: p, intervals: make(map[int]IntervalSummary)}
+}
+
+// Observe handles a session event. WorkoutStarted and Rearmed start a new history, and IntervalEnded reads the
+// results of the interval that just ended; the error is that of the read. Other events are ignored.
+func (r *IntervalRecorder) Observe(ctx context.Context, e SessionEvent) error {
+	switch e.Type {
+	case WorkoutStarted, Rearmed:
+		r.mu.Lock()
+		clear(r.intervals)
+		r.mu.Unlock()
+
+	case IntervalEnded:
+		summary, count, err := fetchLastSplit(ctx, r.p)
+		if err != nil {
+			return fmt.Errorf("record interval: %w", err)
+		}
+		r.mu.Lock()
+		r.intervals[max(count, 1)-1] = summary.Intervals[0]
+		r.mu.Unlock()
+	}
+
+	return nil
+}
+
+// Summary returns the summary of the workout with every interval. Call it once the workout has ended. Single-piece
+// workouts are reported as a single interval; if an interval of an interval workout was not recorded, Summary returns
+// ErrIntervalNotRecorded.
+func (r *IntervalRecorder) Summary(ctx context.Context) (WorkoutSummary, error) {
+	last, count, err := fetchLastSplit(ctx, r.p)
+	if err != nil {
+		return WorkoutSummary{}, fmt.Errorf("workout summary: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// The last interval is still reported by the PM, whether or not its end was seen.
+	n := max(count, 1)
+	r.intervals[n-1] = last.Intervals[0]
+
+	summary := WorkoutSummary{WorkoutType: last.WorkoutType}
+	for i := range n {
+		interval, ok := r.intervals[i]
+		if !ok {
+			return WorkoutSummary{}, fmt.Errorf("workout summary: interval %d: %w", i+1, ErrIntervalNotRecorded)
+		}
+		summary.Intervals = append(summary.Intervals, interval)
+	}
+	return summary, nil
+}
@@ -0,0 +1,110 @@
+package pm5
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFetchWorkoutSummary(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// Variable interval workout whose last interval took 2:00 for 450 m at 2:13.33/500m, 24 spm and 140 bpm, no rest
+	p := respondingPM5(t, ctx,
+		responseReport(MachineStateFinish,
+			0x7E, 0x06, 0x89, 0x01, 0x08, 0x9F, 0x01, 0x01,
+			0x7F, 0x1C,
+			0xBA, 0x04, 0xE0, 0x2E, 0x00, 0x00,
+			0xBC, 0x04, 0xC2, 0x01, 0x00, 0x00,
+			0xAB, 0x04, 0x15, 0x34, 0x00, 0x00,
+			0xB4, 0x01, 0x18,
+			0xB6, 0x01, 0x8C,
+			0xCF, 0x02, 0x00, 0x00,
+		),
+	)
+
+	got, err := FetchWorkoutSummary(ctx, p)
+	if err != nil {
+		t.Fatalf("FetchWorkoutSummary failed: %v", err)
+	}
+
+	want := WorkoutSummary{
+		WorkoutType: WorkoutTypeVariableInterval,
+		Intervals: []IntervalSummary{
+			{Time: 2 * time.Minute, Meters: 450, Pace: 133330 * time.Millisecond, StrokeRate: 24, HeartRate: 140},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("summary mismatch:\ngot:  %+v\nwant: %+v", got, want)
+	}
+}
+
+func TestIntervalRecorder(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	workout := Workout{Intervals: []Interval{
+		{Duration: Meters(250), Rest: 30 * time.Second},
+		{Duration: Time(time.Minute), Rest: 30 * time.Second},
+		{Duration: Meters(500)},
+	}}
+
+	p, e := emulatedPM5(t, ctx, EmulatorConfig{})
+	session := NewSession()
+	recorder := NewIntervalRecorder(p)
+
+	// row advances the workout, feeding the session and recorder every step of the given length.
+	row := func(d, step time.Duration) {
+		for elapsed := time.Duration(0); elapsed < d; elapsed += step {
+			e.Advance(step)
+			state, err := Query[GetWorkoutStateResponse](ctx, p, GetWorkoutState())
+			if err != nil {
+				t.Fatalf("Query failed: %v", err)
+			}
+			for _, ev := range session.Observe(at(int(elapsed/time.Millisecond)), state) {
+				if err := recorder.Observe(ctx, ev); err != nil {
+					t.Fatalf("Observe failed: %v", err)
+				}
+			}
+		}
+	}
+
+	if err := ProgramWorkout(ctx, p, workout); err != nil {
+		t.Fatalf("ProgramWorkout failed: %v", err)
+	}
+	row(10*time.Minute, 5*time.Second)
+
+	summary, err := recorder.Summary(ctx)
+	if err != nil {
+		t.Fatalf("Summary failed: %v", err)
+	}
+	if summary.WorkoutType != WorkoutTypeVariableInterval || len(summary.Intervals) != len(workout.Intervals) {
+		t.Fatalf("summary mismatch: %+v", summary)
+	}
+	for i, interval := range summary.Intervals {
+		if interval.Rest != workout.Intervals[i].Rest || interval.Pace != 2*time.Minute {
+			t.Errorf("interval %d mismatch: %+v", i+1, interval)
+		}
+	}
+	if m := summary.Intervals[0].Meters; m != 250 {
+		t.Errorf("first interval distance: got %d, want 250", m)
+	}
+	if d := summary.Intervals[1].Time; d != time.Minute {
+		t.Errorf("second interval time: got %v, want 1m0s", d)
+	}
+
+	// The intervals of the previous workout are forgotten once the next one starts, so intervals whose end was not
+	// seen are reported missing.
+	if err := ProgramWorkout(ctx, p, workout); err != nil {
+		t.Fatalf("ProgramWorkout failed: %v", err)
+	}
+	row(5*time.Second, 5*time.Second)
+	e.Advance(10 * time.Minute)
+
+	if _, err := recorder.Summary(ctx); !errors.Is(err, ErrIntervalNotRecorded) {
+		t.Errorf("Summary: got %v, want %v", err, ErrIntervalNotRecorded)
+	}
+}
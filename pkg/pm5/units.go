@@ -0,0 +1,70 @@
+package pm5
+
+import (
+	"fmt"
+	"time"
+)
+
+// CSAFE units specifiers for distance, as reported by GetHorizontal and GetOdometer.
+const (
+	DistanceUnitsMile           = 0x01
+	DistanceUnitsTenthMile      = 0x02
+	DistanceUnitsHundredthMile  = 0x03
+	DistanceUnitsThousandthMile = 0x04
+	DistanceUnitsFeet           = 0x05
+	DistanceUnitsKilometer      = 0x21
+	DistanceUnitsHectometer     = 0x22
+	DistanceUnitsDecameter      = 0x23
+	DistanceUnitsMeter          = 0x24
+)
+
+// CSAFE units specifiers for pace, as reported by GetPace.
+const (
+	PaceUnitsSecondsPerKilometer = 0x39
+	PaceUnitsSecondsPerMile      = 0x3A
+)
+
+const metersPerMile = 1609.344
+
+// distanceMeters converts a distance in the given CSAFE units to meters.
+func distanceMeters(distance int, units byte) (float64, error) {
+	// Multiplying before dividing keeps results such as 123 tenths of a mile free of rounding noise.
+	var meters, per float64
+	switch units {
+	case DistanceUnitsMile:
+		meters, per = metersPerMile, 1
+	case DistanceUnitsTenthMile:
+		meters, per = metersPerMile, 10
+	case DistanceUnitsHundredthMile:
+		meters, per = metersPerMile, 100
+	case DistanceUnitsThousandthMile:
+		meters, per = metersPerMile, 1000
+	case DistanceUnitsFeet:
+		meters, per = 0.3048, 1
+	case DistanceUnitsKilometer:
+		meters, per = 1000, 1
+	case DistanceUnitsHectometer:
+		meters, per = 100, 1
+	case DistanceUnitsDecameter:
+		meters, per = 10, 1
+	case DistanceUnitsMeter:
+		meters, per = 1, 1
+	default:
+		return 0, fmt.Errorf("%w: unknown distance units 0x%02X", ErrMalformedResponse, units)
+	}
+	return float64(distance) * meters / per, nil
+}
+
+// pacePer500m converts a pace in the given CSAFE units to the time per 500 m.
+func pacePer500m(pace int, units byte) (time.Duration, error) {
+	var meters float64
+	switch units {
+	case PaceUnitsSecondsPerKilometer:
+		meters = 1000
+	case PaceUnitsSecondsPerMile:
+		meters = metersPerMile
+	default:
+		return 0, fmt.Errorf("%w: unknown pace units 0x%02X", ErrMalformedResponse, units)
+	}
+	return time.Duration(float64(pace) * 500 / meters * float64(time.Second)).Round(time.Millisecond), nil
+}
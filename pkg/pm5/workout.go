@@ -0,0 +1,195 @@
+package pm5
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidWorkout is returned by ProgramWorkout when a Workout cannot be expressed as a PM workout.
+var ErrInvalidWorkout = errors.New("invalid workout")
+
+// Workout describes a workout to program with ProgramWorkout. The fields that are set select the kind of workout:
+//
+//   - nothing: Just Row with splits
+//   - Duration: a single fixed time, distance, calorie or watt-minute piece, split by Split
+//   - Duration and Rest: fixed intervals of Duration separated by Rest, repeated until the rower stops
+//   - Intervals: a variable interval workout; the other fields except TargetPace are ignored
+type Workout struct {
+	Duration WorkoutDuration
+	// Split is the split length of a single piece and must have the same type as Duration. A zero Split programs a
+	// time or distance piece without splits, and a calorie or watt-minute piece as a single split.
+	Split WorkoutDuration
+	Rest  time.Duration
+	// Intervals lists the intervals of a variable interval workout in order.
+	Intervals []Interval
+	// TargetPace is the pace per 500 m for the pace boat. Intervals without a target pace of their own use it too.
+	TargetPace time.Duration
+}
+
+// Interval is one interval of a variable interval workout.
+type Interval struct {
+	Duration   WorkoutDuration
+	Rest       time.Duration
+	TargetPace time.Duration
+}
+
+// ProgramWorkout programs w into the PM and moves it to the workout screen, ready to row. The configuration
+// commands are sent in the order given by the PM5 CSAFE specification, one frame per interval for variable
+// interval workouts. Every frame must be accepted: if the PM rejects one, ProgramWorkout stops and returns the
+// *FrameStatusError.
+func ProgramWorkout(ctx context.Context, p *PM5, w Workout) error {
+	steps, err := w.steps()
+	if err != nil {
+		return fmt.Errorf("program workout: %w", err)
+	}
+
+	for i, commands := range steps {
+		if _, err := p.exchange(ctx, setPMCfg(commands...)); err != nil {
+			return fmt.Errorf("program workout: step %d of %d: %w", i+1, len(steps), err)
+		}
+	}
+
+	return nil
+}
+
+// steps returns the PM-specific configuration commands for the workout, grouped by the frame they are sent in.
+func (w Workout) steps() ([][]Command, error) {
+	start := setScreenState(ScreenTypeWorkout, ScreenValueWorkoutPrepareToRow)
+
+	switch {
+	case len(w.Intervals) > 0:
+		return w.variableIntervalSteps(start)
+	case w.Duration.IsZero():
+		return [][]Command{{setWorkoutType(WorkoutTypeJustRowSplits), start}}, nil
+	case w.Rest > 0:
+		return w.fixedIntervalSteps(start)
+	default:
+		return w.singlePieceSteps(start)
+	}
+}
+
+// workoutType returns the PM workout type that w is programmed as.
+func (w Workout) workoutType() (WorkoutType, error) {
+	switch {
+	case len(w.Intervals) > 0:
+		return WorkoutTypeVariableInterval, nil
+	case w.Duration.IsZero():
+		return WorkoutTypeJustRowSplits, nil
+	case w.Rest > 0:
+		switch w.Duration.Type {
+		case DurationTypeTime:
+			return WorkoutTypeFixedTimeInterval, nil
+		case DurationTypeDistance:
+			return WorkoutTypeFixedDistanceInterval, nil
+		case DurationTypeCalories:
+			return WorkoutTypeFixedCalorieInterval, nil
+		}
+		return 0, fmt.Errorf("%w: fixed intervals of duration type 0x%02X are not supported", ErrInvalidWorkout, w.Duration.Type)
+	}
+
+	switch w.Duration.Type {
+	case DurationTypeTime:
+		if w.Split.IsZero() {
+			return WorkoutTypeFixedTimeNoSplits, nil
+		}
+		return WorkoutTypeFixedTimeSplits, nil
+	case DurationTypeDistance:
+		if w.Split.IsZero() {
+			return WorkoutTypeFixedDistanceNoSplits, nil
+		}
+		return WorkoutTypeFixedDistanceSplits, nil
+	case DurationTypeCalories:
+		return WorkoutTypeFixedCalorieSplits, nil
+	case DurationTypeWattMinutes:
+		return WorkoutTypeFixedWattMinuteSplits, nil
+	}
+	return 0, fmt.Errorf("%w: unknown duration type 0x%02X", ErrInvalidWorkout, w.Duration.Type)
+}
+
+func (w Workout) singlePieceSteps(start Command) ([][]Command, error) {
+	split := w.Split
+	if !split.IsZero() && split.Type != w.Duration.Type {
+		return nil, fmt.Errorf("%w: split type 0x%02X does not match duration type 0x%02X", ErrInvalidWorkout, split.Type, w.Duration.Type)
+	}
+
+	workoutType, err := w.workoutType()
+	if err != nil {
+		return nil, err
+	}
+
+	commands := []Command{setWorkoutType(workoutType), setWorkoutDuration(w.Duration)}
+	if split.IsZero() && (w.Duration.Type == DurationTypeCalories || w.Duration.Type == DurationTypeWattMinutes) {
+		split = w.Duration
+	}
+	if !split.IsZero() {
+		commands = append(commands, setSplitDuration(split))
+	}
+	if w.TargetPace > 0 {
+		commands = append(commands, setTargetPaceTime(w.TargetPace))
+	}
+	commands = append(commands, configureWorkout(true), start)
+
+	return [][]Command{commands}, nil
+}
+
+func (w Workout) fixedIntervalSteps(start Command) ([][]Command, error) {
+	workoutType, err := w.workoutType()
+	if err != nil {
+		return nil, err
+	}
+
+	commands := []Command{setWorkoutType(workoutType), setWorkoutDuration(w.Duration), setRestDuration(w.Rest)}
+	if w.TargetPace > 0 {
+		commands = append(commands, setTargetPaceTime(w.TargetPace))
+	}
+	commands = append(commands, configureWorkout(true), start)
+
+	return [][]Command{commands}, nil
+}
+
+func (w Workout) variableIntervalSteps(start Command) ([][]Command, error) {
+	var steps [][]Command
+	for i, interval := range w.Intervals {
+		if interval.Duration.IsZero() {
+			return nil, fmt.Errorf("%w: interval %d has no duration", ErrInvalidWorkout, i+1)
+		}
+
+		var intervalType IntervalType
+		switch interval.Duration.Type {
+		case DurationTypeTime:
+			intervalType = IntervalTypeTime
+		case DurationTypeDistance:
+			intervalType = IntervalTypeDistance
+		case DurationTypeCalories:
+			intervalType = IntervalTypeCalorie
+		case DurationTypeWattMinutes:
+			intervalType = IntervalTypeWattMinute
+		default:
+			return nil, fmt.Errorf("%w: interval %d has unknown duration type 0x%02X", ErrInvalidWorkout, i+1, interval.Duration.Type)
+		}
+
+		commands := []Command{setWorkoutIntervalCount(i)}
+		if i == 0 {
+			commands = append(commands, setWorkoutType(WorkoutTypeVariableInterval))
+		}
+		commands = append(commands,
+			setIntervalType(intervalType),
+			setWorkoutDuration(interval.Duration),
+			setRestDuration(interval.Rest),
+		)
+
+		pace := interval.TargetPace
+		if pace == 0 {
+			pace = w.TargetPace
+		}
+		if pace > 0 {
+			commands = append(commands, setTargetPaceTime(pace))
+		}
+
+		steps = append(steps, append(commands, configureWorkout(true)))
+	}
+
+	return append(steps, []Command{start}), nil
+}
@@ -0,0 +1,144 @@
+package pm5
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWorkoutSteps(t *testing.T) {
+	tests := []struct {
+		name    string
+		workout Workout
+		want    [][]byte
+	}{
+		{
+			name:    "just row",
+			workout: Workout{},
+			want: [][]byte{
+				{0x76, 0x07, 0x01, 0x01, 0x01, 0x13, 0x02, 0x01, 0x01},
+			},
+		},
+		{
+			// Example from the PM5 CSAFE specification: 2000 m with 500 m splits
+			name:    "fixed distance",
+			workout: Workout{Duration: Meters(2000), Split: Meters(500)},
+			want: [][]byte{
+				{0x76, 0x18, 0x01, 0x01, 0x03, 0x03, 0x05, 0x80, 0x00, 0x00, 0x07, 0xD0, 0x05, 0x05, 0x80, 0x00, 0x00, 0x01, 0xF4, 0x14, 0x01, 0x01, 0x13, 0x02, 0x01, 0x01},
+			},
+		},
+		{
+			name:    "fixed time without splits and target pace",
+			workout: Workout{Duration: Time(20 * time.Minute), TargetPace: 2 * time.Minute},
+			want: [][]byte{
+				{0x76, 0x17, 0x01, 0x01, 0x04, 0x03, 0x05, 0x00, 0x00, 0x01, 0xD4, 0xC0, 0x06, 0x04, 0x00, 0x00, 0x2E, 0xE0, 0x14, 0x01, 0x01, 0x13, 0x02, 0x01, 0x01},
+			},
+		},
+		{
+			name:    "fixed calorie as a single split",
+			workout: Workout{Duration: Calories(100)},
+			want: [][]byte{
+				{0x76, 0x18, 0x01, 0x01, 0x0A, 0x03, 0x05, 0x40, 0x00, 0x00, 0x00, 0x64, 0x05, 0x05, 0x40, 0x00, 0x00, 0x00, 0x64, 0x14, 0x01, 0x01, 0x13, 0x02, 0x01, 0x01},
+			},
+		},
+		{
+			name:    "fixed time intervals",
+			workout: Workout{Duration: Time(2 * time.Minute), Rest: time.Minute},
+			want: [][]byte{
+				{0x76, 0x15, 0x01, 0x01, 0x06, 0x03, 0x05, 0x00, 0x00, 0x00, 0x2E, 0xE0, 0x04, 0x02, 0x00, 0x3C, 0x14, 0x01, 0x01, 0x13, 0x02, 0x01, 0x01},
+			},
+		},
+		{
+			name: "variable intervals",
+			workout: Workout{Intervals: []Interval{
+				{Duration: Time(time.Minute), Rest: 30 * time.Second},
+				{Duration: Meters(500), TargetPace: 110 * time.Second},
+			}},
+			want: [][]byte{
+				{0x76, 0x17, 0x18, 0x01, 0x00, 0x01, 0x01, 0x08, 0x17, 0x01, 0x00, 0x03, 0x05, 0x00, 0x00, 0x00, 0x17, 0x70, 0x04, 0x02, 0x00, 0x1E, 0x14, 0x01, 0x01},
+				{0x76, 0x1A, 0x18, 0x01, 0x01, 0x17, 0x01, 0x01, 0x03, 0x05, 0x80, 0x00, 0x00, 0x01, 0xF4, 0x04, 0x02, 0x00, 0x00, 0x06, 0x04, 0x00, 0x00, 0x2A, 0xF8, 0x14, 0x01, 0x01},
+				{0x76, 0x04, 0x13, 0x02, 0x01, 0x01},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			steps, err := tt.workout.steps()
+			if err != nil {
+				t.Fatalf("steps failed: %v", err)
+			}
+			if len(steps) != len(tt.want) {
+				t.Fatalf("got %d steps, want %d", len(steps), len(tt.want))
+			}
+			for i, commands := range steps {
+				if got := setPMCfg(commands...); !bytes.Equal(got, tt.want[i]) {
+					t.Errorf("step %d mismatch:\ngot:  % X\nwant: % X", i+1, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWorkoutStepsInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		workout Workout
+	}{
+		{name: "split type mismatch", workout: Workout{Duration: Meters(2000), Split: Time(time.Minute)}},
+		{name: "watt-minute intervals", workout: Workout{Duration: WattMinutes(100), Rest: time.Minute}},
+		{name: "empty interval", workout: Workout{Intervals: []Interval{{Rest: time.Minute}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.workout.steps(); !errors.Is(err, ErrInvalidWorkout) {
+				t.Errorf("expected ErrInvalidWorkout, got %v", err)
+			}
+		})
+	}
+}
+
+func TestProgramWorkout(t *testing.T) {
+	workout := Workout{Intervals: []Interval{
+		{Duration: Time(time.Minute), Rest: 30 * time.Second},
+		{Duration: Meters(500), Rest: 30 * time.Second},
+	}}
+
+	t.Run("accepted", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		p := respondingPM5(t, ctx,
+			responseReport(MachineStateReady, 0x76, 0x00),
+			responseReport(0x80|MachineStateReady, 0x76, 0x00),
+		)
+		if err := ProgramWorkout(ctx, p, workout); err != nil {
+			t.Fatalf("ProgramWorkout failed: %v", err)
+		}
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		p := respondingPM5(t, ctx, responseReport(FrameStatusReject|MachineStateReady))
+
+		var statusErr *FrameStatusError
+		if err := ProgramWorkout(ctx, p, workout); !errors.As(err, &statusErr) || statusErr.Status != FrameStatusReject {
+			t.Errorf("expected a rejected FrameStatusError, got %v", err)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		p := respondingPM5(t, ctx)
+		if err := ProgramWorkout(ctx, p, Workout{Intervals: []Interval{{}}}); !errors.Is(err, ErrInvalidWorkout) {
+			t.Errorf("expected ErrInvalidWorkout, got %v", err)
+		}
+	})
+}
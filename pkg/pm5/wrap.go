@@ -0,0 +1,49 @@
+package pm5
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/seagrayinc/gorow/internal/csafe"
+)
+
+// isWrapper reports whether a command wraps PM-specific commands.
+func isWrapper(command byte) bool {
+	switch command {
+	case csafe_SETUSERCFG1_CMD, csafe_SETPMCFG_CMD, csafe_SETPMDATA_CMD, csafe_GETPMCFG_CMD, csafe_GETPMDATA_CMD:
+		return true
+	default:
+		return false
+	}
+}
+
+// wrap nests one or more PM-specific commands in a wrapper command.
+func wrap(wrapper byte, commands ...Command) Command {
+	return csafe.LongCommand(wrapper, bytes.Join(asBytes(commands), nil))
+}
+
+// unwrap returns every PM-specific response nested in a wrapper response. Set commands carry no response data, so the
+// PM acknowledges the commands of a set wrapper with just their IDs.
+func unwrap(r csafe.Response) ([]csafe.Response, error) {
+	if r.Command == csafe_SETPMCFG_CMD || r.Command == csafe_SETPMDATA_CMD {
+		acks := make([]csafe.Response, len(r.Data))
+		for i, id := range r.Data {
+			acks[i] = csafe.Response{Command: id}
+		}
+		return acks, nil
+	}
+
+	responses, err := csafe.ParseResponses(r.Data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: wrapper 0x%02X: %w", ErrMalformedResponse, r.Command, err)
+	}
+	return responses, nil
+}
+
+func asBytes(commands []Command) [][]byte {
+	b := make([][]byte, len(commands))
+	for i, c := range commands {
+		b[i] = c
+	}
+	return b
+}
@@ -0,0 +1,108 @@
+package pm5
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"reflect"
+	"testing"
+
+	"github.com/seagrayinc/gorow/internal/csafe"
+)
+
+func TestWrappedCommands(t *testing.T) {
+	tests := []struct {
+		name    string
+		command Command
+		want    []byte
+	}{
+		{name: "GetStrokeStats", command: GetStrokeStats(), want: []byte{0x7F, 0x03, 0x6E, 0x01, 0x00}},
+		{name: "GetStrokeState", command: GetStrokeState(), want: []byte{0x7F, 0x01, 0xBF}},
+		{name: "GetWorkoutState", command: GetWorkoutState(), want: []byte{0x7E, 0x01, 0x8D}},
+		{
+			name:    "multiple nested commands",
+			command: setPMCfg(csafe.LongCommand(0x01, []byte{0x03}), csafe.LongCommand(0x13, []byte{0x01, 0x01})),
+			want:    []byte{0x76, 0x07, 0x01, 0x01, 0x03, 0x13, 0x02, 0x01, 0x01},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !bytes.Equal(tt.command, tt.want) {
+				t.Errorf("command mismatch:\ngot:  % x\nwant: % x", []byte(tt.command), tt.want)
+			}
+		})
+	}
+}
+
+func TestUnwrap(t *testing.T) {
+	tests := []struct {
+		name     string
+		response csafe.Response
+		want     []csafe.Response
+		wantErr  error
+	}{
+		{
+			name:     "every nested response is returned",
+			response: csafe.Response{Command: csafe_GETPMDATA_CMD, Data: []byte{0xBF, 0x01, 0x02, 0xB3, 0x01, 0x1C}},
+			want: []csafe.Response{
+				{Command: 0xBF, DataByteCount: 1, Data: []byte{0x02}},
+				{Command: 0xB3, DataByteCount: 1, Data: []byte{0x1C}},
+			},
+		},
+		{
+			name:     "set commands acknowledged by ID",
+			response: csafe.Response{Command: csafe_SETPMCFG_CMD, Data: []byte{0x01, 0x03, 0x05}},
+			want:     []csafe.Response{{Command: 0x01}, {Command: 0x03}, {Command: 0x05}},
+		},
+		{
+			name:     "set acknowledgements that parse as a response",
+			response: csafe.Response{Command: csafe_SETPMDATA_CMD, Data: []byte{0x03, 0x01, 0x05}},
+			want:     []csafe.Response{{Command: 0x03}, {Command: 0x01}, {Command: 0x05}},
+		},
+		{
+			name:     "truncated get response",
+			response: csafe.Response{Command: csafe_GETPMCFG_CMD, Data: []byte{0x8D, 0x02, 0x01}},
+			wantErr:  ErrMalformedResponse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := unwrap(tt.response)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error mismatch: got %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("responses mismatch:\ngot:  %+v\nwant: %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseResponsesFromEveryWrapper(t *testing.T) {
+	frame := csafe.ExtendedResponseFrame{
+		ResponseStatus: csafe.ResponseStatus{StateMachineState: MachineStateInUse},
+		CommandResponses: []csafe.Response{
+			{Command: csafe_GETPMDATA_CMD, Data: []byte{0xBF, 0x01, 0x02}},
+			{Command: csafe_GETPMCFG_CMD, Data: []byte{0x8D, 0x01, 0x01}},
+			{Command: csafe_SETUSERCFG1_CMD, Data: []byte{0xBF, 0x01, 0x04}},
+			{Command: csafe_SETPMCFG_CMD, Data: []byte{0x01, 0x13}},
+		},
+	}
+
+	got, err := parseResponses(slog.Default(), frame)
+	if err != nil {
+		t.Fatalf("parseResponses failed: %v", err)
+	}
+
+	want := []any{
+		GetStatusResponse{StateMachineState: MachineStateInUse},
+		GetStrokeStateResponse{StrokeState: 2},
+		GetWorkoutStateResponse{WorkoutState: 1, WorkoutStateString: "Workout row"},
+		GetStrokeStateResponse{StrokeState: 4},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("responses mismatch:\ngot:  %+v\nwant: %+v", got, want)
+	}
+}